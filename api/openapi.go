@@ -0,0 +1,39 @@
+package api
+
+import (
+	"go-react-template/configs"
+	"go-react-template/pkg/handler"
+	"go-react-template/pkg/middleware"
+	"go-react-template/pkg/openapi"
+	"go-react-template/pkg/version"
+
+	"github.com/labstack/echo/v4"
+)
+
+// documentedPrefix 需要有接口文档的路由前缀.
+const documentedPrefix = "/api/v1/"
+
+// OpenAPI 返回所有 /api/v1 接口的文档. 接口声明在 routes.go 中随路由一起注册，
+// 这里以空的处理器注册一遍路由来收集声明，处理器不会被调用.
+func OpenAPI() *openapi.Spec {
+	return SetupRoutes(echo.New(), &handler.UserHandler{}, &handler.HealthHandler{}, &handler.DocsHandler{}, &middleware.SessionMiddleware{}, &middleware.RateLimiter{})
+}
+
+// newSpec 创建只包含文档基本信息的接口文档.
+func newSpec() *openapi.Spec {
+	return openapi.New(openapi.Info{
+		Title:   "go-react-template API",
+		Version: version.Version,
+		Description: "成功响应为 {code: 0, data, message}，失败响应为 {code: 1, data: null, message, error: {code, fields}}，错误码见 docs/api.md。" +
+			"除 GET 外的接口需要通过 X-CSRF-Token 请求头提交 GET /api/v1/auth/csrf 返回的令牌。" +
+			"接口按配置限流，响应携带 RateLimit-* 响应头，超出限制时返回 429 和 Retry-After。",
+		SessionCookie: configs.Default().Session.CookieName,
+		CSRFHeader:    middleware.HeaderXCSRFToken,
+	}, handler.APIResponse{})
+}
+
+// CheckOpenAPI 检查 /api/v1 下的路由和接口声明是否一一对应，返回发现的问题.
+// 没有通过 route 注册的 /api/v1 路由会被报告为缺少文档.
+func CheckOpenAPI(spec *openapi.Spec, routes []*echo.Route) []string {
+	return spec.Check(routes, documentedPrefix)
+}
@@ -0,0 +1,44 @@
+package api
+
+import (
+	"net/http"
+	"testing"
+
+	"go-react-template/pkg/handler"
+	"go-react-template/pkg/middleware"
+	"go-react-template/pkg/openapi"
+
+	"github.com/labstack/echo/v4"
+)
+
+func TestOpenAPICoversRoutes(t *testing.T) {
+	// 只需要路由表，处理器不会被调用
+	e := echo.New()
+	spec := SetupRoutes(e, &handler.UserHandler{}, &handler.HealthHandler{}, &handler.DocsHandler{}, &middleware.SessionMiddleware{}, &middleware.RateLimiter{})
+
+	for _, problem := range CheckOpenAPI(spec, e.Routes()) {
+		t.Error(problem)
+	}
+}
+
+func TestRouteUsesRegisteredPath(t *testing.T) {
+	e := echo.New()
+	r := &router{spec: newSpec()}
+
+	r.route(e.Group("/api/v1").Group("/items"), http.MethodDelete, "/:id", func(echo.Context) error { return nil }, openapi.Operation{
+		OperationID: "deleteItem",
+		Summary:     "删除",
+	})
+
+	ops := r.spec.Operations()
+	if len(ops) != 1 || ops[0].Method != http.MethodDelete || ops[0].Path != "/api/v1/items/:id" {
+		t.Fatalf("接口声明应使用注册的方法和完整路径，got %+v", ops)
+	}
+
+	e.GET("/api/v1/undocumented", func(echo.Context) error { return nil })
+
+	problems := CheckOpenAPI(r.spec, e.Routes())
+	if len(problems) != 1 || problems[0] != "路由缺少文档: GET /api/v1/undocumented" {
+		t.Fatalf("没有通过 route 注册的路由应报告缺少文档，got %v", problems)
+	}
+}
@@ -2,58 +2,169 @@
 package api
 
 import (
+	"net/http"
+
+	"go-react-template/configs"
 	"go-react-template/pkg/handler"
 	"go-react-template/pkg/middleware"
+	"go-react-template/pkg/model"
+	"go-react-template/pkg/openapi"
+	"go-react-template/pkg/service"
 
 	"github.com/labstack/echo/v4"
 )
 
-// SetupRoutes 设置所有API路由.
-func SetupRoutes(e *echo.Echo, userHandler *handler.UserHandler) {
-	// API v1 路由组
-	api := e.Group("/api/v1")
+// SetupRoutes 设置所有API路由并返回 /api/v1 接口的文档. /api/v1 路由通过 route 注册，同时给出接口声明.
+// /api/v1 下的所有接口按 global 策略限流，注册登录接口另按 auth 策略、需要登录的接口另按 user 策略限流.
+func SetupRoutes(e *echo.Echo, userHandler *handler.UserHandler, healthHandler *handler.HealthHandler, docsHandler *handler.DocsHandler, sessions *middleware.SessionMiddleware, limiter *middleware.RateLimiter) *openapi.Spec {
+	// 存活和就绪检查，供容器编排系统探测
+	e.GET("/livez", healthHandler.Livez)
+	e.GET("/readyz", healthHandler.Readyz)
+
+	// 接口文档
+	e.GET("/api/openapi.json", docsHandler.Spec)
+	e.GET("/api/docs", docsHandler.Redirect)
+	e.GET("/api/docs/*", docsHandler.UI)
+
+	// API v1 路由组，修改数据的请求需要携带 CSRF 令牌
+	api := e.Group("/api/v1", limiter.Limit(configs.RateLimitGlobal), sessions.CSRF())
+	r := &router{spec: newSpec()}
 
 	// 设置公开路由（无需认证）
-	setupPublicRoutes(api, userHandler)
+	r.setupPublicRoutes(api, userHandler, healthHandler, limiter)
 
 	// 设置受保护路由（需要认证）
-	setupProtectedRoutes(api, userHandler)
+	r.setupProtectedRoutes(api, userHandler, sessions, limiter)
+
+	return r.spec
+}
+
+// router 注册 /api/v1 路由并收集接口声明.
+type router struct {
+	spec *openapi.Spec
+}
+
+// route 在路由组中注册路由并添加接口声明，声明的方法和完整路径取自注册结果.
+func (r *router) route(g *echo.Group, method, path string, h echo.HandlerFunc, op openapi.Operation, m ...echo.MiddlewareFunc) {
+	registered := g.Add(method, path, h, m...)
+	op.Method = registered.Method
+	op.Path = registered.Path
+
+	r.spec.Add(op)
 }
 
 // setupPublicRoutes 设置公开路由（无需认证）.
-func setupPublicRoutes(api *echo.Group, userHandler *handler.UserHandler) {
+func (r *router) setupPublicRoutes(api *echo.Group, userHandler *handler.UserHandler, healthHandler *handler.HealthHandler, limiter *middleware.RateLimiter) {
 	// 健康检查
-	api.GET("/health", func(c echo.Context) error {
-		return c.JSON(200, map[string]interface{}{
-			"success": true,
-			"data": map[string]interface{}{
-				"status":  "healthy",
-				"service": "go-react-template",
-				"version": "1.0.0",
-			},
-			"message": "服务正常运行",
-		})
+	r.route(api, http.MethodGet, "/health", healthHandler.Health, openapi.Operation{
+		OperationID: "getHealth",
+		Tag:         "system",
+		Summary:     "服务状态（兼容旧格式）",
+		Description: "已弃用，请使用 GET /readyz。执行与 /readyz 相同的就绪检查，未通过时返回 503，响应结构相同，success 为 false、data.status 为 unhealthy。",
+		Response:    handler.LegacyHealthResponse{},
+		Raw:         true,
+		Deprecated:  true,
 	})
 
 	// 认证相关路由（公开）
 	auth := api.Group("/auth")
-	auth.POST("/register", userHandler.Register)
-	auth.POST("/login", userHandler.Login)
-	auth.POST("/google", userHandler.GoogleLogin) // Google第三方登录
+	r.route(auth, http.MethodGet, "/csrf", userHandler.CSRFToken, openapi.Operation{
+		OperationID: "getCsrfToken",
+		Tag:         "auth",
+		Summary:     "获取 CSRF 令牌",
+		Description: "令牌与当前会话绑定，没有会话时同时通过 Set-Cookie 创建一个未登录的会话。登录等操作更换会话后旧令牌失效，新令牌在该操作响应的 X-CSRF-Token 响应头中返回。",
+		Response:    handler.CSRFTokenResponse{},
+	})
+
+	// 注册和登录接口单独限流，防止暴力破解和滥用 Google 令牌校验
+	authLimit := limiter.Limit(configs.RateLimitAuth)
+	r.route(auth, http.MethodPost, "/register", userHandler.Register, openapi.Operation{
+		OperationID: "register",
+		Tag:         "auth",
+		Summary:     "注册",
+		Request:     model.UserRegisterRequest{},
+		Response:    model.UserResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusConflict, http.StatusTooManyRequests},
+	}, authLimit)
+	r.route(auth, http.MethodPost, "/login", userHandler.Login, openapi.Operation{
+		OperationID: "login",
+		Tag:         "auth",
+		Summary:     "邮箱密码登录",
+		Description: "登录成功后通过 Set-Cookie 下发会话 Cookie；remember_me 为 true 时同时下发持久登录令牌 Cookie，会话过期后访问需要登录的接口会自动恢复登录。",
+		Request:     model.UserLoginRequest{},
+		Response:    service.LoginResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusTooManyRequests},
+	}, authLimit)
+	r.route(auth, http.MethodPost, "/google", userHandler.GoogleLogin, openapi.Operation{
+		OperationID: "googleLogin",
+		Tag:         "auth",
+		Summary:     "Google 登录",
+		Description: "使用 Google ID Token 登录，首次登录时自动创建用户。remember_me 的含义与邮箱密码登录相同。",
+		Request:     model.GoogleLoginRequest{},
+		Response:    service.LoginResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusUnauthorized, http.StatusForbidden, http.StatusConflict, http.StatusTooManyRequests},
+	}, authLimit)
 }
 
 // setupProtectedRoutes 设置受保护路由（需要认证）.
-func setupProtectedRoutes(api *echo.Group, userHandler *handler.UserHandler) {
-	// 创建受保护的路由组，应用Session中间件
-	protected := api.Group("", middleware.Session())
+func (r *router) setupProtectedRoutes(api *echo.Group, userHandler *handler.UserHandler, sessions *middleware.SessionMiddleware, limiter *middleware.RateLimiter) {
+	// 创建受保护的路由组，应用Session中间件，认证通过后按用户限流
+	protected := api.Group("", sessions.SessionAuth(), limiter.Limit(configs.RateLimitUser))
 
 	// 受保护的认证路由
 	protectedAuth := protected.Group("/auth")
-	protectedAuth.POST("/logout", userHandler.Logout) // 用户注销
+	r.route(protectedAuth, http.MethodPost, "/logout", userHandler.Logout, openapi.Operation{
+		OperationID: "logout",
+		Tag:         "auth",
+		Summary:     "注销",
+		Auth:        true,
+	})
 
 	// 受保护的用户路由
 	userRoutes := protected.Group("/user")
-	userRoutes.GET("/profile", userHandler.GetProfile)              // 获取当前用户资料
-	userRoutes.PUT("/profile", userHandler.UpdateProfile)           // 更新个人资料
-	userRoutes.POST("/change-password", userHandler.ChangePassword) // 更改密码
+	r.route(userRoutes, http.MethodGet, "/profile", userHandler.GetProfile, openapi.Operation{
+		OperationID: "getProfile",
+		Tag:         "user",
+		Summary:     "获取当前用户资料",
+		Auth:        true,
+		Response:    model.UserResponse{},
+		Errors:      []int{http.StatusNotFound},
+	})
+	r.route(userRoutes, http.MethodPut, "/profile", userHandler.UpdateProfile, openapi.Operation{
+		OperationID: "updateProfile",
+		Tag:         "user",
+		Summary:     "更新个人资料",
+		Description: "只更新非空字段。",
+		Auth:        true,
+		Request:     model.UserUpdateProfileRequest{},
+		Response:    model.UserResponse{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound, http.StatusConflict},
+	})
+	r.route(userRoutes, http.MethodPost, "/change-password", userHandler.ChangePassword, openapi.Operation{
+		OperationID: "changePassword",
+		Tag:         "user",
+		Summary:     "修改密码",
+		Description: "同时吊销所有持久登录令牌，当前浏览器记住登录时换发新的令牌。",
+		Auth:        true,
+		Request:     model.UserChangePasswordRequest{},
+		Errors:      []int{http.StatusBadRequest, http.StatusNotFound},
+	})
+
+	// "记住我"持久登录令牌
+	r.route(userRoutes, http.MethodGet, "/login-tokens", userHandler.ListLoginTokens, openapi.Operation{
+		OperationID: "listLoginTokens",
+		Tag:         "user",
+		Summary:     "获取记住登录的设备",
+		Description: "列出未过期的持久登录令牌，最近使用的在前；current 表示当前浏览器持有的令牌。",
+		Auth:        true,
+		Response:    []model.LoginTokenResponse{},
+	})
+	r.route(userRoutes, http.MethodDelete, "/login-tokens/:id", userHandler.RevokeLoginToken, openapi.Operation{
+		OperationID: "revokeLoginToken",
+		Tag:         "user",
+		Summary:     "退出记住登录的设备",
+		Description: "吊销持久登录令牌，该设备用令牌恢复的会话立即失效，登录时创建的会话过期后需要重新登录。",
+		Auth:        true,
+		Errors:      []int{http.StatusNotFound},
+	})
 }
@@ -0,0 +1,41 @@
+// i18ncheck 检查消息目录中各语言相对默认语言缺少的翻译，有缺失时以非零状态退出，供 CI 使用
+package main
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"go-react-template/pkg/i18n"
+)
+
+func main() {
+	messages, err := i18n.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "消息目录加载失败:", err)
+		os.Exit(1)
+	}
+
+	missing := messages.Missing()
+	if len(missing) == 0 {
+		fmt.Printf("✅ 消息目录完整，语言: %v\n", messages.Locales())
+		return
+	}
+
+	locales := make([]string, 0, len(missing))
+	for locale := range missing {
+		locales = append(locales, locale)
+	}
+
+	sort.Strings(locales)
+
+	for _, locale := range locales {
+		fmt.Printf("❌ %s 缺少 %d 条翻译:\n", locale, len(missing[locale]))
+
+		for _, key := range missing[locale] {
+			fmt.Printf("  - %s\n", key)
+		}
+	}
+
+	os.Exit(1)
+}
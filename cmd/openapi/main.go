@@ -0,0 +1,54 @@
+// openapi 输出 OpenAPI 文档，或检查 /api/v1 路由是否都有接口声明（-check，有问题时以非零状态退出，供 CI 使用）
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+
+	"go-react-template/api"
+	"go-react-template/pkg/handler"
+	"go-react-template/pkg/middleware"
+
+	"github.com/labstack/echo/v4"
+)
+
+func main() {
+	output := flag.String("o", "", "文档输出路径，为空时输出到标准输出")
+	check := flag.Bool("check", false, "只检查路由和接口声明是否一致")
+	flag.Parse()
+
+	if *check {
+		// 只需要路由表，处理器不会被调用
+		e := echo.New()
+		spec := api.SetupRoutes(e, &handler.UserHandler{}, &handler.HealthHandler{}, &handler.DocsHandler{}, &middleware.SessionMiddleware{}, &middleware.RateLimiter{})
+
+		problems := api.CheckOpenAPI(spec, e.Routes())
+		if len(problems) == 0 {
+			fmt.Printf("✅ 接口文档完整，共 %d 个接口\n", len(spec.Operations()))
+			return
+		}
+
+		for _, problem := range problems {
+			fmt.Println("❌", problem)
+		}
+
+		os.Exit(1)
+	}
+
+	data, err := api.OpenAPI().JSON()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "生成接口文档失败:", err)
+		os.Exit(1)
+	}
+
+	if *output == "" {
+		fmt.Println(string(data))
+		return
+	}
+
+	if err := os.WriteFile(*output, append(data, '\n'), 0o644); err != nil { //nolint:gosec // 文档需要可读
+		fmt.Fprintln(os.Stderr, "写入接口文档失败:", err)
+		os.Exit(1)
+	}
+}
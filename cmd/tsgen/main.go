@@ -0,0 +1,69 @@
+// tsgen 根据接口声明生成前端 TypeScript 类型和 API 客户端，或检查已提交的生成代码是否过期（-check，过期时以非零状态退出，供 CI 使用）
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"go-react-template/api"
+)
+
+func main() {
+	dir := flag.String("o", "web/src/api/generated", "生成代码的输出目录")
+	check := flag.Bool("check", false, "只检查生成代码是否与接口声明一致")
+	flag.Parse()
+
+	files := api.OpenAPI().TypeScript()
+
+	names := make([]string, 0, len(files))
+	for name := range files {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	if *check {
+		var stale []string
+
+		for _, name := range names {
+			path := filepath.Join(*dir, name)
+
+			existing, err := os.ReadFile(path) //nolint:gosec // 路径来自命令行参数
+			if err != nil || !bytes.Equal(existing, files[name]) {
+				stale = append(stale, path)
+			}
+		}
+
+		if len(stale) == 0 {
+			fmt.Println("✅ TypeScript 生成代码是最新的")
+			return
+		}
+
+		for _, path := range stale {
+			fmt.Println("❌ 生成代码已过期:", path)
+		}
+
+		fmt.Println("请运行 make tsgen 重新生成")
+		os.Exit(1)
+	}
+
+	if err := os.MkdirAll(*dir, 0o755); err != nil { //nolint:gosec // 源码目录需要可读
+		fmt.Fprintln(os.Stderr, "创建输出目录失败:", err)
+		os.Exit(1)
+	}
+
+	for _, name := range names {
+		path := filepath.Join(*dir, name)
+
+		if err := os.WriteFile(path, files[name], 0o644); err != nil { //nolint:gosec // 源码文件需要可读
+			fmt.Fprintln(os.Stderr, "写入生成代码失败:", err)
+			os.Exit(1)
+		}
+
+		fmt.Println("已生成", path)
+	}
+}
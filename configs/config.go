@@ -1,118 +1,354 @@
 package configs
 
 import (
-	"log"
-	"os"
-	"strconv"
+	"net"
+	"slices"
+	"time"
+)
 
-	"github.com/joho/godotenv"
+// 运行模式.
+const (
+	EnvDevelopment = "development"
+	EnvProduction  = "production"
 )
 
+// DefaultSessionSecret 默认的Session密钥，仅用于本地开发，生产模式下禁止使用.
+const DefaultSessionSecret = "your-secret-key"
+
+// DefaultContentSecurityPolicy 默认的内容安全策略：脚本只允许同源文件和带 nonce 的内联脚本，
+// 并放行 Google 登录（accounts.google.com/gsi）所需的资源.
+const DefaultContentSecurityPolicy = "default-src 'self'; " +
+	"script-src 'self' 'nonce-{nonce}' https://accounts.google.com/gsi/client; " +
+	"style-src 'self' 'unsafe-inline' https://accounts.google.com/gsi/style; " +
+	"img-src 'self' data: https:; " +
+	"font-src 'self' data:; " +
+	"connect-src 'self' https://accounts.google.com/gsi/; " +
+	"frame-src https://accounts.google.com/gsi/; " +
+	"object-src 'none'; base-uri 'self'; form-action 'self'; frame-ancestors 'none'"
+
 // Config 应用配置结构.
 type Config struct {
+	// 应用配置
+	App AppSettings `json:"app" yaml:"app" toml:"app"`
+	// 日志配置
+	Log LogConfig `json:"log" yaml:"log" toml:"log"`
 	// 服务器配置
-	Server ServerConfig `json:"server"`
+	Server ServerConfig `json:"server" yaml:"server" toml:"server"`
+	// 跨域配置
+	CORS CORSConfig `json:"cors" yaml:"cors" toml:"cors"`
+	// 安全响应头配置
+	Security SecurityConfig `json:"security" yaml:"security" toml:"security"`
+	// 限流配置
+	RateLimit RateLimitConfig `json:"rate_limit" yaml:"rate_limit" toml:"rate_limit"`
 	// 数据库配置
-	Database DatabaseConfig `json:"database"`
+	Database DatabaseConfig `json:"database" yaml:"database" toml:"database"`
 	// Session配置
-	Session SessionConfig `json:"session"`
+	Session SessionConfig `json:"session" yaml:"session" toml:"session"`
+	// 功能开关配置
+	Features FeaturesConfig `json:"features" yaml:"features" toml:"features"`
+	// 监控指标配置
+	Metrics MetricsConfig `json:"metrics" yaml:"metrics" toml:"metrics"`
+	// 链路追踪配置
+	Tracing TracingConfig `json:"tracing" yaml:"tracing" toml:"tracing"`
+
+	// File 加载的配置文件路径，为空表示未使用配置文件
+	File string `json:"-" yaml:"-" toml:"-"`
+}
+
+// AppSettings 应用运行配置.
+type AppSettings struct {
+	Env string `json:"env" yaml:"env" toml:"env" env:"APP_ENV" validate:"oneof=development production"` // 运行模式
+}
+
+// LogConfig 日志配置.
+type LogConfig struct {
+	Level  string `json:"level" yaml:"level" toml:"level" env:"LOG_LEVEL" validate:"oneof=debug info warn error" reload:"hot"` // 日志级别 (debug, info, warn, error)
+	Format string `json:"format" yaml:"format" toml:"format" env:"LOG_FORMAT" validate:"oneof=text json"`                      // 输出格式 (text, json)
 }
 
 // ServerConfig 服务器配置.
 type ServerConfig struct {
-	Port string `json:"port"` // 监听端口
-	Host string `json:"host"` // 监听地址
+	Port string `json:"port" yaml:"port" toml:"port" env:"SERVER_PORT" validate:"required,numeric"` // 监听端口
+	Host string `json:"host" yaml:"host" toml:"host" env:"SERVER_HOST"`                             // 监听地址
+
+	StaticDir string `json:"static_dir" yaml:"static_dir" toml:"static_dir" env:"SERVER_STATIC_DIR"` // 前端静态文件目录，设置后代替嵌入的前端资源，便于开发时使用本地构建产物
+
+	TrustedProxies []string `json:"trusted_proxies" yaml:"trusted_proxies" toml:"trusted_proxies" env:"SERVER_TRUSTED_PROXIES" validate:"dive,cidr|ip"` // 可信反向代理的地址或网段，只采信来自这些地址的 X-Forwarded-* 请求头，默认只有本机
+
+	ShutdownDelay   time.Duration `json:"shutdown_delay" yaml:"shutdown_delay" toml:"shutdown_delay" env:"SERVER_SHUTDOWN_DELAY" validate:"gte=0" reload:"hot"`          // 收到退出信号后就绪检查先失败，等待该时间再停止接收请求，便于负载均衡摘除实例
+	ShutdownTimeout time.Duration `json:"shutdown_timeout" yaml:"shutdown_timeout" toml:"shutdown_timeout" env:"SERVER_SHUTDOWN_TIMEOUT" validate:"min=1s" reload:"hot"` // 等待处理中请求完成的最长时间，超时后强制断开
+}
+
+// CORSConfig 跨域配置，支持热加载.
+type CORSConfig struct {
+	AllowOrigins     []string      `json:"allow_origins" yaml:"allow_origins" toml:"allow_origins" env:"CORS_ALLOW_ORIGINS" validate:"dive,url|eq=*" reload:"hot"`                                     // 允许的来源，如 https://example.com，* 表示任意来源
+	AllowMethods     []string      `json:"allow_methods" yaml:"allow_methods" toml:"allow_methods" env:"CORS_ALLOW_METHODS" validate:"dive,oneof=GET HEAD POST PUT PATCH DELETE OPTIONS" reload:"hot"` // 允许的请求方法
+	AllowCredentials bool          `json:"allow_credentials" yaml:"allow_credentials" toml:"allow_credentials" env:"CORS_ALLOW_CREDENTIALS" reload:"hot"`                                              // 是否允许跨域请求携带 Cookie
+	MaxAge           time.Duration `json:"max_age" yaml:"max_age" toml:"max_age" env:"CORS_MAX_AGE" validate:"gte=0" reload:"hot"`                                                                     // 浏览器缓存预检结果的时间，0 表示不缓存
+}
+
+// SecurityConfig 安全响应头配置，支持热加载.
+type SecurityConfig struct {
+	HSTSMaxAge            time.Duration `json:"hsts_max_age" yaml:"hsts_max_age" toml:"hsts_max_age" env:"SECURITY_HSTS_MAX_AGE" validate:"gte=0" reload:"hot"`                            // HTTPS 请求返回 Strict-Transport-Security 的有效期，0 表示不发送
+	HSTSIncludeSubdomains bool          `json:"hsts_include_subdomains" yaml:"hsts_include_subdomains" toml:"hsts_include_subdomains" env:"SECURITY_HSTS_INCLUDE_SUBDOMAINS" reload:"hot"` // HSTS 是否包含子域名
+	ReferrerPolicy        string        `json:"referrer_policy" yaml:"referrer_policy" toml:"referrer_policy" env:"SECURITY_REFERRER_POLICY" reload:"hot"`                                 // Referrer-Policy，为空时不发送
+	PermissionsPolicy     string        `json:"permissions_policy" yaml:"permissions_policy" toml:"permissions_policy" env:"SECURITY_PERMISSIONS_POLICY" reload:"hot"`                     // Permissions-Policy，为空时不发送
+	ContentSecurityPolicy string        `json:"content_security_policy" yaml:"content_security_policy" toml:"content_security_policy" env:"SECURITY_CSP" reload:"hot"`                     // Content-Security-Policy，{nonce} 替换为每个请求的随机值，为空时不发送
+	CSPReportOnly         bool          `json:"csp_report_only" yaml:"csp_report_only" toml:"csp_report_only" env:"SECURITY_CSP_REPORT_ONLY" reload:"hot"`                                 // 使用 Content-Security-Policy-Report-Only，只报告不拦截，便于上线新策略前观察
+}
+
+// RateLimitConfig 限流配置，除存储后端外支持热加载.
+//
+// 每组路由的速率格式为 <请求数>/<周期>（如 10/1m），为空表示不限流；限流键 ip 按客户端IP，user 按登录用户，
+// token 按服务端校验过的登录凭证（登录会话），user 和 token 未登录时按IP.
+type RateLimitConfig struct {
+	Enabled    bool     `json:"enabled" yaml:"enabled" toml:"enabled" env:"RATE_LIMIT_ENABLED" reload:"hot"`                 // 是否开启限流
+	Backend    string   `json:"backend" yaml:"backend" toml:"backend" env:"RATE_LIMIT_BACKEND" validate:"oneof=memory"`      // 限流状态存储 (memory)
+	AdminUsers []string `json:"admin_users" yaml:"admin_users" toml:"admin_users" env:"RATE_LIMIT_ADMIN_USERS" reload:"hot"` // 管理员用户ID，不受限流
+
+	GlobalLimit string `json:"global_limit" yaml:"global_limit" toml:"global_limit" env:"RATE_LIMIT_GLOBAL" validate:"ratelimit" reload:"hot"`         // 所有 /api/v1 接口
+	GlobalKey   string `json:"global_key" yaml:"global_key" toml:"global_key" env:"RATE_LIMIT_GLOBAL_KEY" validate:"oneof=ip user token" reload:"hot"` // 限流键 (ip, user, token)
+	AuthLimit   string `json:"auth_limit" yaml:"auth_limit" toml:"auth_limit" env:"RATE_LIMIT_AUTH" validate:"ratelimit" reload:"hot"`                 // 注册、登录、Google 登录
+	AuthKey     string `json:"auth_key" yaml:"auth_key" toml:"auth_key" env:"RATE_LIMIT_AUTH_KEY" validate:"oneof=ip user token" reload:"hot"`         // 限流键 (ip, user, token)
+	UserLimit   string `json:"user_limit" yaml:"user_limit" toml:"user_limit" env:"RATE_LIMIT_USER" validate:"ratelimit" reload:"hot"`                 // 需要登录的接口
+	UserKey     string `json:"user_key" yaml:"user_key" toml:"user_key" env:"RATE_LIMIT_USER_KEY" validate:"oneof=ip user token" reload:"hot"`         // 限流键 (ip, user, token)
+}
+
+// RateLimitPolicy 一组路由的限流策略.
+type RateLimitPolicy struct {
+	Limit string // 速率，为空表示不限流
+	Key   string // 限流键 (ip, user, token)
+}
+
+// 限流的路由组.
+const (
+	RateLimitGlobal = "global"
+	RateLimitAuth   = "auth"
+	RateLimitUser   = "user"
+)
+
+// Policies 返回各路由组的限流策略.
+func (c RateLimitConfig) Policies() map[string]RateLimitPolicy {
+	return map[string]RateLimitPolicy{
+		RateLimitGlobal: {Limit: c.GlobalLimit, Key: c.GlobalKey},
+		RateLimitAuth:   {Limit: c.AuthLimit, Key: c.AuthKey},
+		RateLimitUser:   {Limit: c.UserLimit, Key: c.UserKey},
+	}
 }
 
 // DatabaseConfig 数据库配置.
 type DatabaseConfig struct {
-	Driver   string `json:"driver"`   // 数据库驱动 (sqlite, mysql, postgres)
-	Host     string `json:"host"`     // 数据库主机
-	Port     string `json:"port"`     // 数据库端口
-	Username string `json:"username"` // 用户名
-	Password string `json:"password"` // 密码
-	DBName   string `json:"dbname"`   // 数据库名
-	SSLMode  string `json:"sslmode"`  // SSL模式
-	Path     string `json:"path"`     // SQLite数据库文件路径
+	Driver   string `json:"driver" yaml:"driver" toml:"driver" env:"DB_DRIVER" validate:"oneof=sqlite mysql postgres"` // 数据库驱动 (sqlite, mysql, postgres)
+	Host     string `json:"host" yaml:"host" toml:"host" env:"DB_HOST"`                                                // 数据库主机
+	Port     string `json:"port" yaml:"port" toml:"port" env:"DB_PORT" validate:"omitempty,numeric"`                   // 数据库端口
+	Username string `json:"username" yaml:"username" toml:"username" env:"DB_USERNAME"`                                // 用户名
+	Password string `json:"password" yaml:"password" toml:"password" env:"DB_PASSWORD"`                                // 密码
+	DBName   string `json:"dbname" yaml:"dbname" toml:"dbname" env:"DB_NAME"`                                          // 数据库名
+	SSLMode  string `json:"sslmode" yaml:"sslmode" toml:"sslmode" env:"DB_SSLMODE"`                                    // SSL模式
+	Path     string `json:"path" yaml:"path" toml:"path" env:"DB_PATH" validate:"required_if=Driver sqlite"`           // SQLite数据库文件路径
+
+	QueryTimeout time.Duration `json:"query_timeout" yaml:"query_timeout" toml:"query_timeout" env:"DB_QUERY_TIMEOUT" validate:"gte=0"` // 单次数据库操作超时，0 表示不限制
+
+	// 连接池配置
+	MaxOpenConns    int           `json:"max_open_conns" yaml:"max_open_conns" toml:"max_open_conns" env:"DB_MAX_OPEN_CONNS" validate:"gte=0"`                 // 最大打开连接数，0 表示不限制
+	MaxIdleConns    int           `json:"max_idle_conns" yaml:"max_idle_conns" toml:"max_idle_conns" env:"DB_MAX_IDLE_CONNS" validate:"gte=0"`                 // 最大空闲连接数
+	ConnMaxLifetime time.Duration `json:"conn_max_lifetime" yaml:"conn_max_lifetime" toml:"conn_max_lifetime" env:"DB_CONN_MAX_LIFETIME" validate:"gte=0"`     // 连接最大存活时间，0 表示不限制
+	ConnMaxIdleTime time.Duration `json:"conn_max_idle_time" yaml:"conn_max_idle_time" toml:"conn_max_idle_time" env:"DB_CONN_MAX_IDLE_TIME" validate:"gte=0"` // 连接最大空闲时间，0 表示不限制
+
+	// 只读副本配置，与主库使用相同的用户名、密码和数据库名
+	ReplicaHosts       []string      `json:"replica_hosts" yaml:"replica_hosts" toml:"replica_hosts" env:"DB_REPLICA_HOSTS" validate:"excluded_if=Driver sqlite,dive,hostname_port|hostname"` // 只读副本地址列表 (host 或 host:port)
+	ReadYourWritesTime time.Duration `json:"read_your_writes" yaml:"read_your_writes" toml:"read_your_writes" env:"DB_READ_YOUR_WRITES" validate:"gte=0"`                                     // 用户写入后该时间内的读取走主库，0 表示关闭
+
+	// SQL日志配置
+	LogLevel      string        `json:"log_level" yaml:"log_level" toml:"log_level" env:"DB_LOG_LEVEL" validate:"oneof=silent error warn info" reload:"hot"` // SQL日志级别 (silent, error, warn, info)
+	SlowThreshold time.Duration `json:"slow_threshold" yaml:"slow_threshold" toml:"slow_threshold" env:"DB_SLOW_THRESHOLD" validate:"gte=0" reload:"hot"`    // 慢查询阈值，0 表示不检测
+	LogParams     bool          `json:"log_params" yaml:"log_params" toml:"log_params" env:"DB_LOG_PARAMS"`                                                  // 日志中是否输出SQL绑定参数，默认以占位符代替
 }
 
-// SessionConfig Session配置.
+// SessionConfig Session配置. 会话数据签名后保存在Cookie中，注销和更换掉的会话ID记录在数据库中，认证时拒绝.
 type SessionConfig struct {
-	Secret     string `json:"secret"`      // Session密钥
-	ExpireHour int    `json:"expire_hour"` // 过期时间(小时)
+	Secret      string        `json:"secret" yaml:"secret" toml:"secret" env:"SESSION_SECRET" validate:"required"`                      // Session密钥
+	ExpireHour  int           `json:"expire_hour" yaml:"expire_hour" toml:"expire_hour" env:"SESSION_EXPIRE_HOUR" validate:"min=1"`     // 登录后的最长有效期(小时)，到期后必须重新登录
+	IdleTimeout time.Duration `json:"idle_timeout" yaml:"idle_timeout" toml:"idle_timeout" env:"SESSION_IDLE_TIMEOUT" validate:"gte=0"` // 无活动超时，0 表示不限制
+
+	// 滑动续期配置
+	RefreshAfter float64       `json:"refresh_after" yaml:"refresh_after" toml:"refresh_after" env:"SESSION_REFRESH_AFTER" validate:"gte=0,lt=1"` // 有效期过去该比例后访问时续期，0 表示不续期
+	MaxLifetime  time.Duration `json:"max_lifetime" yaml:"max_lifetime" toml:"max_lifetime" env:"SESSION_MAX_LIFETIME" validate:"gte=0"`          // 续期的上限，从登录时间开始计算
+
+	// "记住我"配置
+	RememberMeTTL time.Duration `json:"remember_me_ttl" yaml:"remember_me_ttl" toml:"remember_me_ttl" env:"SESSION_REMEMBER_ME_TTL" validate:"gte=0"` // 持久登录令牌有效期，每次使用后重新计算，0 表示不支持"记住我"
+
+	// Cookie配置
+	CookieName     string        `json:"cookie_name" yaml:"cookie_name" toml:"cookie_name" env:"SESSION_COOKIE_NAME" validate:"required"`                                 // Cookie名称
+	CookieDomain   string        `json:"cookie_domain" yaml:"cookie_domain" toml:"cookie_domain" env:"SESSION_COOKIE_DOMAIN"`                                             // Cookie域名，为空时只发送给当前主机
+	CookieSecure   bool          `json:"cookie_secure" yaml:"cookie_secure" toml:"cookie_secure" env:"SESSION_COOKIE_SECURE"`                                             // 总是只通过 HTTPS 发送，生产模式下强制开启
+	CookieSameSite string        `json:"cookie_same_site" yaml:"cookie_same_site" toml:"cookie_same_site" env:"SESSION_COOKIE_SAMESITE" validate:"oneof=lax strict none"` // SameSite (lax, strict, none)
+	CookieMaxAge   time.Duration `json:"cookie_max_age" yaml:"cookie_max_age" toml:"cookie_max_age" env:"SESSION_COOKIE_MAX_AGE" validate:"gte=0"`                        // Cookie有效期，0 表示浏览器关闭后失效
 }
 
-// AppConfig 全局配置实例.
-var AppConfig *Config
+// FeaturesConfig 功能开关配置，支持热加载.
+type FeaturesConfig struct {
+	Enabled []string `json:"enabled" yaml:"enabled" toml:"enabled" env:"FEATURES_ENABLED" reload:"hot"` // 启用的功能开关
+}
 
-// Init 初始化配置.
-func Init() error {
-	// 尝试加载.env文件
-	if err := godotenv.Load(); err != nil {
-		log.Println("警告: 未找到.env文件，将使用环境变量或默认值")
-	}
+// MetricsConfig Prometheus 指标配置.
+type MetricsConfig struct {
+	Enabled bool   `json:"enabled" yaml:"enabled" toml:"enabled" env:"METRICS_ENABLED"`                  // 是否开放 /metrics，默认关闭
+	Port    string `json:"port" yaml:"port" toml:"port" env:"METRICS_PORT" validate:"omitempty,numeric"` // 独立管理端口，为空时与业务共用端口（只允许在开发模式下使用）
+}
 
-	// 创建配置实例
-	AppConfig = &Config{
+// TracingConfig OpenTelemetry 链路追踪配置.
+type TracingConfig struct {
+	Exporter    string  `json:"exporter" yaml:"exporter" toml:"exporter" env:"TRACING_EXPORTER" validate:"oneof=none stdout otlp"`      // 导出器 (none, stdout, otlp)
+	Endpoint    string  `json:"endpoint" yaml:"endpoint" toml:"endpoint" env:"TRACING_OTLP_ENDPOINT"`                                   // OTLP/HTTP 接收端地址 (host:port)，为空时使用 OTEL_EXPORTER_OTLP_* 环境变量或默认值
+	Insecure    bool    `json:"insecure" yaml:"insecure" toml:"insecure" env:"TRACING_OTLP_INSECURE"`                                   // OTLP 使用 HTTP 而不是 HTTPS
+	ServiceName string  `json:"service_name" yaml:"service_name" toml:"service_name" env:"TRACING_SERVICE_NAME" validate:"required"`    // 上报的服务名
+	SampleRatio float64 `json:"sample_ratio" yaml:"sample_ratio" toml:"sample_ratio" env:"TRACING_SAMPLE_RATIO" validate:"gte=0,lte=1"` // 采样比例 0-1
+}
+
+// Default 返回内置默认配置，是配置分层中的最底层.
+func Default() *Config {
+	return &Config{
+		App: AppSettings{
+			Env: EnvDevelopment,
+		},
+		Log: LogConfig{
+			Level:  "info",
+			Format: "text",
+		},
 		Server: ServerConfig{
-			Port: getEnv("SERVER_PORT", "1323"),
-			Host: getEnv("SERVER_HOST", "0.0.0.0"),
+			Port: "1323",
+			Host: "0.0.0.0",
+
+			TrustedProxies: []string{"127.0.0.0/8", "::1/128"}, // 只信任本机，经过其他反向代理时需要显式配置
+
+			ShutdownTimeout: 15 * time.Second,
+		},
+		CORS: CORSConfig{
+			AllowOrigins:     []string{"http://localhost:5173", "http://localhost:3000"},
+			AllowMethods:     []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+			AllowCredentials: true,
+		},
+		Security: SecurityConfig{
+			HSTSMaxAge:            365 * 24 * time.Hour,
+			ReferrerPolicy:        "strict-origin-when-cross-origin",
+			PermissionsPolicy:     "camera=(), microphone=(), geolocation=(), payment=(), usb=()",
+			ContentSecurityPolicy: DefaultContentSecurityPolicy,
+		},
+		RateLimit: RateLimitConfig{
+			Enabled:     true,
+			Backend:     "memory",
+			GlobalLimit: "600/1m",
+			GlobalKey:   "user",
+			AuthLimit:   "10/1m",
+			AuthKey:     "ip",
+			UserLimit:   "120/1m",
+			UserKey:     "user",
 		},
 		Database: DatabaseConfig{
-			Driver:   getEnv("DB_DRIVER", "sqlite"),
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnv("DB_PORT", "3306"),
-			Username: getEnv("DB_USERNAME", ""),
-			Password: getEnv("DB_PASSWORD", ""),
-			DBName:   getEnv("DB_NAME", "go_react_template"),
-			SSLMode:  getEnv("DB_SSLMODE", "disable"),
-			Path:     getEnv("DB_PATH", "app.db"),
+			Driver:  "sqlite",
+			Host:    "localhost",
+			Port:    "3306",
+			DBName:  "go_react_template",
+			SSLMode: "disable",
+			Path:    "app.db",
+
+			QueryTimeout: 5 * time.Second,
+
+			MaxOpenConns:    25,
+			MaxIdleConns:    10,
+			ConnMaxLifetime: 30 * time.Minute,
+			ConnMaxIdleTime: 5 * time.Minute,
+
+			ReadYourWritesTime: 5 * time.Second,
+
+			LogLevel:      "warn",
+			SlowThreshold: 200 * time.Millisecond,
 		},
 		Session: SessionConfig{
-			Secret:     getEnv("SESSION_SECRET", "your-secret-key"),
-			ExpireHour: getEnvAsInt("SESSION_EXPIRE_HOUR", 24),
+			Secret:      DefaultSessionSecret,
+			ExpireHour:  24,
+			IdleTimeout: 2 * time.Hour,
+
+			MaxLifetime: 7 * 24 * time.Hour,
+
+			RememberMeTTL: 30 * 24 * time.Hour,
+
+			CookieName:     "user-session",
+			CookieSameSite: "lax",
+			CookieMaxAge:   24 * time.Hour,
+		},
+		Tracing: TracingConfig{
+			Exporter:    "none",
+			ServiceName: "go-react-template",
+			SampleRatio: 1,
 		},
 	}
+}
 
-	log.Printf("配置初始化完成: 服务器将在 %s:%s 启动", AppConfig.Server.Host, AppConfig.Server.Port)
-
-	return nil
+// IsProduction 是否运行在生产模式.
+func (c *Config) IsProduction() bool {
+	return c.App.Env == EnvProduction
 }
 
-// getEnv 获取环境变量，如果不存在则返回默认值.
-func getEnv(key, defaultValue string) string {
-	if value := os.Getenv(key); value != "" {
-		return value
-	}
+// FeatureEnabled 判断功能开关是否启用.
+func (c *Config) FeatureEnabled(name string) bool {
+	return slices.Contains(c.Features.Enabled, name)
+}
 
-	return defaultValue
+// GetDatabaseDSN 获取数据库连接字符串.
+func (c *Config) GetDatabaseDSN() string {
+	return c.databaseDSN(c.Database.Host, c.Database.Port)
 }
 
-// getEnvAsInt 获取环境变量并转换为整数，如果不存在或转换失败则返回默认值.
-func getEnvAsInt(key string, defaultValue int) int {
-	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
+// GetReplicaDSNs 获取只读副本的连接字符串，未指定端口时使用主库端口.
+func (c *Config) GetReplicaDSNs() []string {
+	dsns := make([]string, 0, len(c.Database.ReplicaHosts))
+
+	for _, addr := range c.Database.ReplicaHosts {
+		host, port, err := net.SplitHostPort(addr)
+		if err != nil {
+			host, port = addr, c.Database.Port
 		}
+
+		dsns = append(dsns, c.databaseDSN(host, port))
 	}
 
-	return defaultValue
+	return dsns
 }
 
-// GetDatabaseDSN 获取数据库连接字符串.
-func (c *Config) GetDatabaseDSN() string {
+// databaseDSN 使用指定的主机和端口生成连接字符串.
+func (c *Config) databaseDSN(host, port string) string {
 	switch c.Database.Driver {
 	case "sqlite":
 		return c.Database.Path
 	case "mysql":
-		return c.Database.Username + ":" + c.Database.Password + "@tcp(" + c.Database.Host + ":" + c.Database.Port + ")/" + c.Database.DBName + "?charset=utf8mb4&parseTime=True&loc=Local"
+		return c.Database.Username + ":" + c.Database.Password + "@tcp(" + host + ":" + port + ")/" + c.Database.DBName + "?charset=utf8mb4&parseTime=True&loc=Local"
 	case "postgres":
-		return "host=" + c.Database.Host + " user=" + c.Database.Username + " password=" + c.Database.Password + " dbname=" + c.Database.DBName + " port=" + c.Database.Port + " sslmode=" + c.Database.SSLMode + " TimeZone=Asia/Shanghai"
+		return "host=" + host + " user=" + c.Database.Username + " password=" + c.Database.Password + " dbname=" + c.Database.DBName + " port=" + port + " sslmode=" + c.Database.SSLMode + " TimeZone=Asia/Shanghai"
 	default:
 		return c.Database.Path // 默认使用SQLite
 	}
 }
 
+// GetMetricsAddress 获取独立指标端口的监听地址，未配置独立端口时返回空字符串.
+func (c *Config) GetMetricsAddress() string {
+	if c.Metrics.Port == "" {
+		return ""
+	}
+
+	return c.Server.Host + ":" + c.Metrics.Port
+}
+
+// GetSessionLifetime 返回会话从登录开始的最长存活时间：开启滑动续期时为续期上限，否则为最长有效期.
+func (c *Config) GetSessionLifetime() time.Duration {
+	if c.Session.RefreshAfter > 0 {
+		return c.Session.MaxLifetime
+	}
+
+	return time.Duration(c.Session.ExpireHour) * time.Hour
+}
+
 // GetServerAddress 获取服务器监听地址.
 func (c *Config) GetServerAddress() string {
 	return c.Server.Host + ":" + c.Server.Port
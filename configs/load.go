@@ -0,0 +1,288 @@
+package configs
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"gopkg.in/yaml.v3"
+)
+
+// fileEnvSuffix 以该后缀结尾的环境变量指向一个文件，文件内容作为对应变量的值（用于Docker/Kubernetes secrets）.
+const fileEnvSuffix = "_FILE"
+
+// Init 使用命令行参数初始化配置，返回用于热加载的配置管理器. .env 文件需要由调用方在此之前加载.
+func Init() (*Manager, error) {
+	return NewManager(os.Args[1:])
+}
+
+// Load 按 默认值 -> 配置文件 -> 环境变量 -> 命令行参数 的顺序逐层加载配置并校验.
+//
+// 配置文件路径由 -config 参数或 CONFIG_FILE 环境变量指定，支持 .yaml/.yml/.toml.
+// 每个配置项都可以通过与其文件键同名的命令行参数覆盖，例如 -server.port=8080.
+func Load(args []string) (*Config, error) {
+	cfg := Default()
+
+	configFile, overrides, err := parseFlags(cfg, args)
+	if err != nil {
+		return nil, err
+	}
+
+	if configFile == "" {
+		configFile, err = lookupEnv("CONFIG_FILE")
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if configFile != "" {
+		if err := loadFile(cfg, configFile); err != nil {
+			return nil, err
+		}
+
+		cfg.File = configFile
+	}
+
+	if err := applyEnv(cfg); err != nil {
+		return nil, err
+	}
+
+	if err := applyOverrides(cfg, overrides); err != nil {
+		return nil, err
+	}
+
+	if err := Validate(cfg); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// loadFile 从配置文件加载配置，根据扩展名选择解析格式.
+func loadFile(cfg *Config, path string) error {
+	data, err := os.ReadFile(path) //nolint:gosec // 配置文件路径来自运维人员
+	if err != nil {
+		return fmt.Errorf("读取配置文件 %s 失败: %w", path, err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+
+		err = dec.Decode(cfg)
+		if errors.Is(err, io.EOF) {
+			err = nil // 空文件
+		}
+	case ".toml":
+		var meta toml.MetaData
+
+		meta, err = toml.Decode(string(data), cfg)
+		if err == nil {
+			if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+				err = fmt.Errorf("未知的配置项: %v", undecoded)
+			}
+		}
+	default:
+		return fmt.Errorf("不支持的配置文件格式: %s", path)
+	}
+
+	if err != nil {
+		return fmt.Errorf("解析配置文件 %s 失败: %w", path, err)
+	}
+
+	return nil
+}
+
+// applyEnv 使用环境变量覆盖配置.
+func applyEnv(cfg *Config) error {
+	return walkFields(reflect.ValueOf(cfg).Elem(), "", func(field reflect.StructField, value reflect.Value, _ string) error {
+		key := field.Tag.Get("env")
+		if key == "" {
+			return nil
+		}
+
+		raw, err := lookupEnv(key)
+		if err != nil || raw == "" {
+			return err
+		}
+
+		if err := setValue(value, raw); err != nil {
+			return fmt.Errorf("环境变量 %s 的值无效: %w", key, err)
+		}
+
+		return nil
+	})
+}
+
+// lookupEnv 读取环境变量，支持通过 KEY_FILE 从文件读取值.
+func lookupEnv(key string) (string, error) {
+	value := os.Getenv(key)
+
+	path := os.Getenv(key + fileEnvSuffix)
+	if path == "" {
+		return value, nil
+	}
+
+	if value != "" {
+		return "", fmt.Errorf("环境变量 %s 与 %s 不能同时设置", key, key+fileEnvSuffix)
+	}
+
+	data, err := os.ReadFile(path) //nolint:gosec // secret文件路径来自运维人员
+	if err != nil {
+		return "", fmt.Errorf("读取 %s 指定的文件失败: %w", key+fileEnvSuffix, err)
+	}
+
+	return strings.TrimRight(string(data), "\r\n"), nil
+}
+
+// parseFlags 解析命令行参数，返回配置文件路径和需要覆盖的配置项.
+func parseFlags(cfg *Config, args []string) (string, map[string]string, error) {
+	fs := flag.NewFlagSet("server", flag.ContinueOnError)
+
+	configFile := fs.String("config", "", "配置文件路径 (.yaml/.yml/.toml)")
+	overrides := make(map[string]string)
+
+	err := walkFields(reflect.ValueOf(cfg).Elem(), "", func(field reflect.StructField, _ reflect.Value, key string) error {
+		usage := "覆盖配置项 " + key
+		if env := field.Tag.Get("env"); env != "" {
+			usage += " (环境变量 " + env + ")"
+		}
+
+		fs.Func(key, usage, func(raw string) error {
+			overrides[key] = raw
+			return nil
+		})
+
+		return nil
+	})
+	if err != nil {
+		return "", nil, err
+	}
+
+	if err := fs.Parse(args); err != nil {
+		return "", nil, fmt.Errorf("解析命令行参数失败: %w", err)
+	}
+
+	return *configFile, overrides, nil
+}
+
+// applyOverrides 使用命令行参数覆盖配置.
+func applyOverrides(cfg *Config, overrides map[string]string) error {
+	return walkFields(reflect.ValueOf(cfg).Elem(), "", func(_ reflect.StructField, value reflect.Value, key string) error {
+		raw, ok := overrides[key]
+		if !ok {
+			return nil
+		}
+
+		if err := setValue(value, raw); err != nil {
+			return fmt.Errorf("命令行参数 -%s 的值无效: %w", key, err)
+		}
+
+		return nil
+	})
+}
+
+// walkFields 遍历配置结构的所有叶子字段，key 为以点分隔的文件键路径，例如 server.port.
+func walkFields(v reflect.Value, prefix string, fn func(field reflect.StructField, value reflect.Value, key string) error) error {
+	t := v.Type()
+
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if name == "" || name == "-" {
+			continue
+		}
+
+		key := name
+		if prefix != "" {
+			key = prefix + "." + name
+		}
+
+		value := v.Field(i)
+		if value.Kind() == reflect.Struct {
+			if err := walkFields(value, key, fn); err != nil {
+				return err
+			}
+
+			continue
+		}
+
+		if err := fn(field, value, key); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setValue 将字符串解析为字段对应的类型并赋值.
+func setValue(value reflect.Value, raw string) error {
+	if value.Type() == reflect.TypeOf(time.Duration(0)) {
+		d, err := time.ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(int64(d))
+
+		return nil
+	}
+
+	switch value.Kind() { //nolint:exhaustive // 配置只使用以下类型
+	case reflect.String:
+		value.SetString(raw)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(raw, 10, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetInt(n)
+	case reflect.Float32, reflect.Float64:
+		f, err := strconv.ParseFloat(raw, value.Type().Bits())
+		if err != nil {
+			return err
+		}
+
+		value.SetFloat(f)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return err
+		}
+
+		value.SetBool(b)
+	case reflect.Slice:
+		if value.Type().Elem().Kind() != reflect.String {
+			return fmt.Errorf("不支持的配置类型: %s", value.Type())
+		}
+
+		var items []string
+
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+
+		value.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("不支持的配置类型: %s", value.Type())
+	}
+
+	return nil
+}
@@ -0,0 +1,122 @@
+package configs
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// clearEnv 清空测试涉及的环境变量，避免受运行环境影响.
+func clearEnv(t *testing.T, keys ...string) {
+	t.Helper()
+
+	for _, key := range append(keys, "CONFIG_FILE", "APP_ENV") {
+		t.Setenv(key, "")
+		t.Setenv(key+fileEnvSuffix, "")
+	}
+}
+
+// writeFile 在临时目录中写入文件并返回路径.
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatalf("写入文件失败: %v", err)
+	}
+
+	return path
+}
+
+func TestLoadPrecedence(t *testing.T) {
+	file := writeFile(t, "config.yaml", "server:\n  port: \"2000\"\n  host: 127.0.0.1\n")
+
+	tests := []struct {
+		name     string
+		file     bool
+		env      string
+		args     []string
+		wantPort string
+	}{
+		{name: "默认值", wantPort: "1323"},
+		{name: "配置文件覆盖默认值", file: true, wantPort: "2000"},
+		{name: "环境变量覆盖配置文件", file: true, env: "3000", wantPort: "3000"},
+		{name: "命令行参数覆盖环境变量", file: true, env: "3000", args: []string{"-server.port=4000"}, wantPort: "4000"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t, "SERVER_PORT", "SERVER_HOST")
+			t.Setenv("SERVER_PORT", tt.env)
+
+			if tt.file {
+				t.Setenv("CONFIG_FILE", file)
+			}
+
+			cfg, err := Load(tt.args)
+			if err != nil {
+				t.Fatalf("加载配置失败: %v", err)
+			}
+
+			if cfg.Server.Port != tt.wantPort {
+				t.Errorf("server.port = %q, 期望 %q", cfg.Server.Port, tt.wantPort)
+			}
+
+			// 上层没有设置的配置项保留下层的值
+			wantHost := "0.0.0.0"
+			if tt.file {
+				wantHost = "127.0.0.1"
+			}
+
+			if cfg.Server.Host != wantHost {
+				t.Errorf("server.host = %q, 期望 %q", cfg.Server.Host, wantHost)
+			}
+		})
+	}
+}
+
+func TestLoadSecretFile(t *testing.T) {
+	clearEnv(t, "SESSION_SECRET")
+	t.Setenv("SESSION_SECRET_FILE", writeFile(t, "secret", "from-file-secret\n"))
+
+	cfg, err := Load(nil)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	if cfg.Session.Secret != "from-file-secret" {
+		t.Errorf("session.secret = %q, 期望读取文件内容并去掉换行", cfg.Session.Secret)
+	}
+}
+
+func TestLoadSecretFileErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		value   string
+		file    string
+		wantErr string
+	}{
+		{name: "文件不存在", file: filepath.Join(os.TempDir(), "no-such-secret-file"), wantErr: "SESSION_SECRET_FILE"},
+		{name: "同时设置变量和文件", value: "inline", file: "secret", wantErr: "不能同时设置"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			clearEnv(t, "SESSION_SECRET")
+			t.Setenv("SESSION_SECRET", tt.value)
+
+			file := tt.file
+			if file == "secret" {
+				file = writeFile(t, "secret", "from-file")
+			}
+
+			t.Setenv("SESSION_SECRET_FILE", file)
+
+			_, err := Load(nil)
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, 期望包含 %q", err, tt.wantErr)
+			}
+		})
+	}
+}
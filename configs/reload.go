@@ -0,0 +1,261 @@
+package configs
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+// reloadDebounce 配置文件连续变更时的合并间隔，编辑器保存时通常会触发多个事件.
+const reloadDebounce = 300 * time.Millisecond
+
+// ReloadResult 一次热加载的结果.
+type ReloadResult struct {
+	Applied         []string // 已生效的配置项
+	RestartRequired []string // 已修改但需要重启才能生效的配置项
+}
+
+// Subscriber 配置变更订阅者，在新配置生效后被调用.
+type Subscriber func(old, updated *Config)
+
+// Manager 持有当前生效的配置，支持监听配置文件和 SIGHUP 信号进行热加载.
+//
+// 只有标记了 reload:"hot" 的配置项会在运行时替换，其余配置项（如数据库驱动、监听地址）
+// 的修改会被报告为需要重启，并保留原值.
+type Manager struct {
+	args    []string
+	static  bool
+	current atomic.Pointer[Config]
+
+	mu          sync.Mutex // 串行化 Reload 和订阅者通知
+	subscribers []Subscriber
+}
+
+// NewManager 加载配置并创建配置管理器.
+func NewManager(args []string) (*Manager, error) {
+	cfg, err := Load(args)
+	if err != nil {
+		return nil, err
+	}
+
+	m := &Manager{args: args}
+	m.current.Store(cfg)
+
+	return m, nil
+}
+
+// NewStaticManager 使用已构建好的配置创建配置管理器，不会从外部重新加载，适用于测试和嵌入场景.
+func NewStaticManager(cfg *Config) *Manager {
+	m := &Manager{static: true}
+	m.current.Store(cfg)
+
+	return m
+}
+
+// Current 返回当前生效的配置，返回值不可修改.
+func (m *Manager) Current() *Config {
+	return m.current.Load()
+}
+
+// Subscribe 注册配置变更订阅者.
+func (m *Manager) Subscribe(fn Subscriber) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.subscribers = append(m.subscribers, fn)
+}
+
+// Reload 重新加载并校验配置，校验失败时保留当前配置不变.
+func (m *Manager) Reload() (*ReloadResult, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.static {
+		return &ReloadResult{}, nil
+	}
+
+	updated, err := Load(m.args)
+	if err != nil {
+		return nil, err
+	}
+
+	old := m.current.Load()
+
+	result, err := mergeReloadable(old, updated)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(result.Applied) == 0 {
+		return result, nil
+	}
+
+	m.current.Store(updated)
+
+	for _, fn := range m.subscribers {
+		fn(old, updated)
+	}
+
+	return result, nil
+}
+
+// Watch 监听配置文件变更和 SIGHUP 信号并触发热加载，直到 ctx 结束. 热加载的结果输出到 logger.
+func (m *Manager) Watch(ctx context.Context, logger *slog.Logger) error {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+
+	defer signal.Stop(hup)
+
+	var fileEvents <-chan fsnotify.Event
+
+	if path := m.Current().File; path != "" && !m.static {
+		watcher, err := fsnotify.NewWatcher()
+		if err != nil {
+			return fmt.Errorf("创建配置文件监听失败: %w", err)
+		}
+		defer watcher.Close()
+
+		// 监听所在目录而不是文件本身，兼容编辑器和 Kubernetes ConfigMap 的原子替换
+		if err := watcher.Add(filepath.Dir(path)); err != nil {
+			return fmt.Errorf("监听配置文件 %s 失败: %w", path, err)
+		}
+
+		fileEvents = filteredEvents(ctx, watcher, path, logger)
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-hup:
+			logger.Info("收到 SIGHUP 信号，重新加载配置")
+			m.reloadAndLog(logger)
+		case <-fileEvents:
+			logger.Info("检测到配置文件变更，重新加载配置")
+			m.reloadAndLog(logger)
+		}
+	}
+}
+
+// reloadAndLog 执行热加载并记录结果.
+func (m *Manager) reloadAndLog(logger *slog.Logger) {
+	result, err := m.Reload()
+	if err != nil {
+		logger.Error("配置热加载失败，继续使用当前配置", "error", err)
+		return
+	}
+
+	for _, key := range result.RestartRequired {
+		logger.Warn("配置项已修改，需要重启后生效", "key", key)
+	}
+
+	if len(result.Applied) > 0 {
+		logger.Info("配置热加载完成", "applied", result.Applied)
+	} else {
+		logger.Info("配置热加载完成，没有可热更新的变更")
+	}
+}
+
+// filteredEvents 将目标文件相关的事件合并去抖后输出.
+func filteredEvents(ctx context.Context, watcher *fsnotify.Watcher, path string, logger *slog.Logger) <-chan fsnotify.Event {
+	out := make(chan fsnotify.Event)
+	target := filepath.Clean(path)
+
+	go func() {
+		var (
+			timer   *time.Timer
+			timerC  <-chan time.Time
+			pending fsnotify.Event
+		)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+
+				if filepath.Clean(event.Name) != target && !isConfigMapSwap(event, path) {
+					continue
+				}
+
+				pending = event
+
+				if timer == nil {
+					timer = time.NewTimer(reloadDebounce)
+				} else {
+					timer.Reset(reloadDebounce)
+				}
+
+				timerC = timer.C
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+
+				logger.Error("配置文件监听错误", "error", err)
+			case <-timerC:
+				timerC = nil
+
+				select {
+				case out <- pending:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+
+	return out
+}
+
+// isConfigMapSwap Kubernetes 挂载的 ConfigMap 通过替换 ..data 符号链接更新文件.
+func isConfigMapSwap(event fsnotify.Event, path string) bool {
+	return filepath.Base(event.Name) == "..data" && filepath.Dir(event.Name) == filepath.Dir(path)
+}
+
+// mergeReloadable 比较新旧配置，将不可热更新的配置项恢复为旧值.
+func mergeReloadable(old, updated *Config) (*ReloadResult, error) {
+	previous := make(map[string]reflect.Value)
+
+	err := walkFields(reflect.ValueOf(old).Elem(), "", func(_ reflect.StructField, value reflect.Value, key string) error {
+		previous[key] = value
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ReloadResult{}
+
+	err = walkFields(reflect.ValueOf(updated).Elem(), "", func(field reflect.StructField, value reflect.Value, key string) error {
+		before := previous[key]
+		if reflect.DeepEqual(before.Interface(), value.Interface()) {
+			return nil
+		}
+
+		if field.Tag.Get("reload") == "hot" {
+			result.Applied = append(result.Applied, key)
+			return nil
+		}
+
+		result.RestartRequired = append(result.RestartRequired, key)
+		value.Set(before)
+
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,100 @@
+package configs
+
+import (
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestManagerReload(t *testing.T) {
+	clearEnv(t, "LOG_LEVEL", "SERVER_PORT")
+
+	file := writeFile(t, "config.yaml", "log:\n  level: info\nserver:\n  port: \"2000\"\n")
+	t.Setenv("CONFIG_FILE", file)
+
+	m, err := NewManager(nil)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	var notified *Config
+
+	m.Subscribe(func(_, updated *Config) {
+		notified = updated
+	})
+
+	// log.level 可以热加载，server.port 需要重启
+	if err := os.WriteFile(file, []byte("log:\n  level: debug\nserver:\n  port: \"3000\"\n"), 0o600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	result, err := m.Reload()
+	if err != nil {
+		t.Fatalf("热加载失败: %v", err)
+	}
+
+	if !slices.Equal(result.Applied, []string{"log.level"}) {
+		t.Errorf("Applied = %v, 期望 [log.level]", result.Applied)
+	}
+
+	if !slices.Equal(result.RestartRequired, []string{"server.port"}) {
+		t.Errorf("RestartRequired = %v, 期望 [server.port]", result.RestartRequired)
+	}
+
+	cfg := m.Current()
+	if cfg.Log.Level != "debug" {
+		t.Errorf("log.level = %q, 期望热加载为 debug", cfg.Log.Level)
+	}
+
+	if cfg.Server.Port != "2000" {
+		t.Errorf("server.port = %q, 需要重启的配置项应保留原值 2000", cfg.Server.Port)
+	}
+
+	if notified != cfg {
+		t.Error("订阅者应收到新的配置")
+	}
+
+	// 新配置校验失败时保留当前配置
+	if err := os.WriteFile(file, []byte("log:\n  level: verbose\n"), 0o600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	if _, err := m.Reload(); err == nil {
+		t.Fatal("非法配置应返回错误")
+	}
+
+	if m.Current() != cfg {
+		t.Error("热加载失败时应继续使用当前配置")
+	}
+}
+
+func TestManagerReloadRestartOnly(t *testing.T) {
+	clearEnv(t, "SERVER_PORT")
+
+	file := writeFile(t, "config.yaml", "server:\n  port: \"2000\"\n")
+	t.Setenv("CONFIG_FILE", file)
+
+	m, err := NewManager(nil)
+	if err != nil {
+		t.Fatalf("加载配置失败: %v", err)
+	}
+
+	before := m.Current()
+
+	if err := os.WriteFile(file, []byte("server:\n  port: \"3000\"\n"), 0o600); err != nil {
+		t.Fatalf("写入配置文件失败: %v", err)
+	}
+
+	result, err := m.Reload()
+	if err != nil {
+		t.Fatalf("热加载失败: %v", err)
+	}
+
+	if len(result.Applied) != 0 || !slices.Equal(result.RestartRequired, []string{"server.port"}) {
+		t.Errorf("result = %+v, 期望只有 server.port 需要重启", result)
+	}
+
+	if m.Current() != before {
+		t.Error("没有可热更新的变更时不应替换当前配置")
+	}
+}
@@ -0,0 +1,110 @@
+package configs
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"go-react-template/pkg/ratelimit"
+
+	"github.com/go-playground/validator/v10"
+)
+
+// minProductionSecretLength 生产模式下Session密钥的最小长度.
+const minProductionSecretLength = 32
+
+// Validate 根据结构体 validate 标签及跨字段规则校验配置，任一项不合法都会返回错误.
+func Validate(cfg *Config) error {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		return name
+	})
+	_ = v.RegisterValidation("ratelimit", func(fl validator.FieldLevel) bool { //nolint:errcheck
+		_, err := ratelimit.ParseLimit(fl.Field().String())
+		return err == nil
+	})
+
+	var problems []string
+
+	if err := v.Struct(cfg); err != nil {
+		var fieldErrs validator.ValidationErrors
+		if !errors.As(err, &fieldErrs) {
+			return fmt.Errorf("配置校验失败: %w", err)
+		}
+
+		for _, fe := range fieldErrs {
+			problems = append(problems, describeFieldError(fe))
+		}
+	}
+
+	if cfg.Metrics.Port != "" && cfg.Metrics.Port == cfg.Server.Port {
+		problems = append(problems, "metrics.port: 不能与 server.port 相同")
+	}
+
+	if cfg.CORS.AllowCredentials && slices.Contains(cfg.CORS.AllowOrigins, "*") {
+		problems = append(problems, "cors.allow_origins: 允许携带 Cookie 时不能使用 *，请列出具体来源")
+	}
+
+	if cfg.Session.RefreshAfter > 0 && cfg.Session.MaxLifetime < time.Duration(cfg.Session.ExpireHour)*time.Hour {
+		problems = append(problems, "session.max_lifetime: 开启滑动续期时不能小于 session.expire_hour")
+	}
+
+	if cfg.IsProduction() {
+		if cfg.Session.Secret == DefaultSessionSecret {
+			problems = append(problems, "session.secret: 生产模式下不能使用默认Session密钥")
+		} else if len(cfg.Session.Secret) < minProductionSecretLength {
+			problems = append(problems, fmt.Sprintf("session.secret: 生产模式下长度不能少于%d个字符", minProductionSecretLength))
+		}
+
+		if cfg.Metrics.Enabled && cfg.Metrics.Port == "" {
+			problems = append(problems, "metrics.port: 生产模式下开启指标时必须配置独立端口，避免在业务端口公开 /metrics")
+		}
+
+		if cfg.Database.LogParams {
+			problems = append(problems, "database.log_params: 生产模式下不能在日志中输出SQL参数")
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("配置校验失败:\n  %s", strings.Join(problems, "\n  "))
+	}
+
+	return nil
+}
+
+// describeFieldError 将字段校验错误转换为可读的描述.
+func describeFieldError(fe validator.FieldError) string {
+	// 去掉根结构名，保留 server.port 形式的路径
+	_, key, _ := strings.Cut(fe.Namespace(), ".")
+
+	switch fe.Tag() {
+	case "required", "required_if":
+		return key + ": 不能为空"
+	case "oneof":
+		return fmt.Sprintf("%s: 取值 %q 无效，可选值: %s", key, fe.Value(), fe.Param())
+	case "lt":
+		return fmt.Sprintf("%s: 必须小于 %s", key, fe.Param())
+	case "min", "gte":
+		return fmt.Sprintf("%s: 不能小于 %s", key, fe.Param())
+	case "max", "lte":
+		return fmt.Sprintf("%s: 不能大于 %s", key, fe.Param())
+	case "excluded_if":
+		return key + ": 当前数据库驱动不支持该配置"
+	case "hostname_port|hostname":
+		return fmt.Sprintf("%s: 取值 %q 不是合法的地址", key, fe.Value())
+	case "url|eq=*":
+		return fmt.Sprintf("%s: 取值 %q 不是合法的来源，应为 scheme://host[:port] 或 *", key, fe.Value())
+	case "cidr|ip":
+		return fmt.Sprintf("%s: 取值 %q 不是合法的 IP 或网段", key, fe.Value())
+	case "ratelimit":
+		return fmt.Sprintf("%s: 取值 %q 不是合法的速率，格式为 <请求数>/<周期>，例如 10/1m", key, fe.Value())
+	case "numeric":
+		return fmt.Sprintf("%s: 取值 %q 不是数字", key, fe.Value())
+	default:
+		return fmt.Sprintf("%s: 不满足规则 %s", key, fe.Tag())
+	}
+}
@@ -0,0 +1,68 @@
+package configs
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidateProduction(t *testing.T) {
+	const strongSecret = "0123456789abcdef0123456789abcdef"
+
+	tests := []struct {
+		name    string
+		modify  func(cfg *Config)
+		wantErr string
+	}{
+		{name: "开发模式允许默认密钥", modify: func(*Config) {}},
+		{
+			name:    "生产模式拒绝默认密钥",
+			modify:  func(cfg *Config) { cfg.App.Env = EnvProduction },
+			wantErr: "session.secret: 生产模式下不能使用默认Session密钥",
+		},
+		{
+			name: "生产模式拒绝过短的密钥",
+			modify: func(cfg *Config) {
+				cfg.App.Env = EnvProduction
+				cfg.Session.Secret = "short"
+			},
+			wantErr: "session.secret: 生产模式下长度不能少于",
+		},
+		{
+			name: "生产模式拒绝输出SQL参数",
+			modify: func(cfg *Config) {
+				cfg.App.Env = EnvProduction
+				cfg.Session.Secret = strongSecret
+				cfg.Database.LogParams = true
+			},
+			wantErr: "database.log_params",
+		},
+		{
+			name: "生产模式的合法配置",
+			modify: func(cfg *Config) {
+				cfg.App.Env = EnvProduction
+				cfg.Session.Secret = strongSecret
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(cfg)
+
+			err := Validate(cfg)
+
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("不应返回错误: %v", err)
+				}
+
+				return
+			}
+
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("err = %v, 期望包含 %q", err, tt.wantErr)
+			}
+		})
+	}
+}
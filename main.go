@@ -1,158 +1,92 @@
 package main
 
 import (
+	"context"
 	"log"
-	"net/http"
 	"os"
-	"path/filepath"
-	"strings"
+	"os/signal"
+	"syscall"
 
-	"go-react-template/api"
 	"go-react-template/configs"
-	"go-react-template/pkg/database"
-	"go-react-template/pkg/handler"
-	"go-react-template/pkg/model"
-	"go-react-template/pkg/repo"
-	"go-react-template/pkg/service"
-
-	"github.com/labstack/echo/v4"
-	"github.com/labstack/echo/v4/middleware"
+	"go-react-template/pkg/app"
+	"go-react-template/pkg/logging"
+	"go-react-template/pkg/version"
+
+	"github.com/joho/godotenv"
 )
 
 func main() {
+	// 加载.env文件，已设置的环境变量优先
+	dotenvErr := godotenv.Load()
+
 	// 初始化配置
-	if err := configs.Init(); err != nil {
+	cfgManager, err := configs.Init()
+	if err != nil {
 		log.Fatal("配置初始化失败:", err)
 	}
 
-	// 初始化数据库
-	if err := database.Init(); err != nil {
-		log.Fatal("数据库初始化失败:", err)
+	// 初始化日志，各组件通过参数接收该日志实例，不使用全局默认日志
+	logger, err := logging.New(cfgManager, os.Stdout)
+	if err != nil {
+		log.Fatal("日志初始化失败:", err)
 	}
 
-	// 执行数据库迁移
-	if err := database.AutoMigrate(&model.User{}); err != nil {
-		log.Fatal("数据库迁移失败:", err)
+	if dotenvErr != nil {
+		logger.Warn("未找到.env文件，将使用环境变量或默认值")
 	}
 
-	// 初始化依赖
-	userRepo := repo.NewUserRepo()
-	userService := service.NewUserService(userRepo)
-	userHandler := handler.NewUserHandler(userService)
-
-	// 创建Echo实例
-	e := echo.New()
-
-	// 添加中间件
-	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
-		LogStatus:   true,
-		LogURI:      true,
-		LogError:    true,
-		HandleError: true,
-		LogValuesFunc: func(_ echo.Context, v middleware.RequestLoggerValues) error {
-			if v.Error == nil {
-				log.Printf("[%d] %s", v.Status, v.URI)
-			} else {
-				log.Printf("[%d] %s - %v", v.Status, v.URI, v.Error)
-			}
-			return nil
-		},
-	}))
-	e.Use(middleware.Recover())
-	// 启用 Gzip 压缩
-	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{
-		Level: 5, // 压缩级别 1-9，5 是性能和压缩率的平衡
-		Skipper: func(c echo.Context) bool {
-			// 跳过已经压缩的资源（如图片）
-			path := c.Request().URL.Path
-			return strings.HasSuffix(path, ".png") ||
-				strings.HasSuffix(path, ".jpg") ||
-				strings.HasSuffix(path, ".jpeg") ||
-				strings.HasSuffix(path, ".gif") ||
-				strings.HasSuffix(path, ".webp") ||
-				strings.HasSuffix(path, ".ico")
-		},
-	}))
-	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
-		AllowOrigins:     []string{"http://localhost:5173", "http://localhost:3000"},
-		AllowMethods:     []string{echo.GET, echo.POST, echo.PUT, echo.DELETE, echo.OPTIONS},
-		AllowHeaders:     []string{echo.HeaderOrigin, echo.HeaderContentType, echo.HeaderAccept, echo.HeaderAuthorization, "X-Language"},
-		AllowCredentials: true,
-	}))
-
-	// 设置API路由
-	api.SetupRoutes(e, userHandler)
-
-	// 设置静态文件服务
-	setupStaticFiles(e)
-
-	serverAddr := configs.AppConfig.GetServerAddress()
-	log.Printf("服务器启动在地址 %s", serverAddr)
-	e.Logger.Fatal(e.Start(serverAddr))
-}
+	cfg := cfgManager.Current()
+	logger.Info("配置初始化完成", "address", cfg.GetServerAddress(), "env", cfg.App.Env, "file", cfg.File)
 
-// setupStaticFiles 设置静态文件服务.
-func setupStaticFiles(e *echo.Echo) {
-	// 静态文件目录
-	staticDir := "static"
+	// 收到 SIGINT/SIGTERM 时 ctx 结束，触发优雅关闭并停止后台任务
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	// 检查静态文件目录是否存在
-	if _, err := os.Stat(staticDir); os.IsNotExist(err) {
-		log.Printf("警告: 静态文件目录 %s 不存在，跳过静态文件服务设置", staticDir)
-		return
+	// 监听配置文件和 SIGHUP 信号，热加载可在运行时更新的配置
+	go func() {
+		if err := cfgManager.Watch(ctx, logger.With("component", "config")); err != nil {
+			logger.Error("配置热加载监听启动失败", "error", err)
+		}
+	}()
+
+	// 创建应用实例
+	application, err := app.New(cfgManager, logger)
+	if err != nil {
+		logger.Error("应用初始化失败", "error", err)
+		os.Exit(1)
 	}
 
-	// 服务带有哈希的静态资源文件（长期缓存）
-	e.GET("/assets/*", func(c echo.Context) error {
-		filePath := filepath.Join(staticDir, c.Request().URL.Path)
-		if _, err := os.Stat(filePath); err != nil {
-			return echo.NewHTTPError(http.StatusNotFound, "File not found")
-		}
-		// 设置强缓存：1年，因为文件名包含哈希值
-		c.Response().Header().Set("Cache-Control", "public, max-age=31536000, immutable")
-
-		return c.File(filePath)
-	})
-
-	// 服务 favicon（短期缓存）
-	e.GET("/favicon.ico", func(c echo.Context) error {
-		c.Response().Header().Set("Cache-Control", "public, max-age=86400") // 1天
-		return c.File(filepath.Join(staticDir, "favicon.ico"))
-	})
-
-	// 服务网站图标 SVG（长期缓存）
-	e.GET("/vite.svg", func(c echo.Context) error {
-		c.Response().Header().Set("Cache-Control", "public, max-age=604800") // 7天
-		return c.File(filepath.Join(staticDir, "vite.svg"))
-	})
-
-	// 处理SPA路由，所有非API请求都返回index.html
-	e.GET("/*", func(c echo.Context) error {
-		path := c.Request().URL.Path
-
-		// 如果是API请求，返回404
-		if len(path) >= 4 && path[:4] == "/api" {
-			return echo.NewHTTPError(http.StatusNotFound, "API endpoint not found")
-		}
+	logger.Info("服务器启动",
+		"address", cfg.GetServerAddress(),
+		"version", version.Version,
+		"commit", version.Commit,
+		"build_time", version.BuildTime,
+	)
 
-		// 检查请求的文件是否存在
-		filePath := filepath.Join(staticDir, path)
-		if path == "/" {
-			filePath = filepath.Join(staticDir, "index.html")
-		}
+	serverErr := make(chan error, 1)
 
-		if _, err := os.Stat(filePath); err == nil {
-			// 对于 HTML 文件，使用协商缓存
-			if filepath.Ext(filePath) == ".html" {
-				c.Response().Header().Set("Cache-Control", "no-cache")
-			}
+	go func() {
+		serverErr <- application.Start()
+	}()
 
-			return c.File(filePath)
-		}
+	select {
+	case err := <-serverErr:
+		application.Close()
 
-		// 文件不存在，返回index.html（SPA路由）
-		c.Response().Header().Set("Cache-Control", "no-cache")
+		if err != nil {
+			logger.Error("服务器运行失败", "error", err)
+			os.Exit(1)
+		}
+	case <-ctx.Done():
+		// 恢复默认信号处理，关闭过程中再次收到信号时立即退出
+		stop()
+		logger.Info("收到退出信号，开始关闭服务")
+
+		if err := application.Shutdown(context.Background()); err != nil {
+			logger.Error("服务关闭失败", "error", err)
+			os.Exit(1)
+		}
 
-		return c.File(filepath.Join(staticDir, "index.html"))
-	})
+		logger.Info("服务已关闭")
+	}
 }
@@ -0,0 +1,325 @@
+// Package app 应用容器，负责在一处组装配置、数据库、Session、服务和HTTP处理器
+package app
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"go-react-template/api"
+	"go-react-template/configs"
+	"go-react-template/pkg/database"
+	"go-react-template/pkg/handler"
+	"go-react-template/pkg/health"
+	"go-react-template/pkg/i18n"
+	"go-react-template/pkg/metrics"
+	"go-react-template/pkg/middleware"
+	"go-react-template/pkg/model"
+	"go-react-template/pkg/ratelimit"
+	"go-react-template/pkg/repo"
+	"go-react-template/pkg/service"
+	"go-react-template/pkg/tracing"
+
+	"github.com/labstack/echo/v4"
+	"gorm.io/gorm"
+)
+
+// healthCheckTimeout 单项就绪检查的超时时间.
+const healthCheckTimeout = 2 * time.Second
+
+// models 需要迁移的数据模型.
+var models = []interface{}{&model.User{}, &model.LoginToken{}, &model.RevokedSession{}}
+
+// App 应用实例，持有所有依赖；同一进程中可以创建多个互不影响的实例.
+type App struct {
+	Config   *configs.Manager
+	Logger   *slog.Logger
+	DB       *gorm.DB
+	DBLogger *database.Logger
+	Metrics  *metrics.Metrics
+	Tracing  *tracing.Provider
+	Sessions *middleware.SessionMiddleware
+	// RateLimiter 按路由组限流的中间件
+	RateLimiter *middleware.RateLimiter
+	Messages    *i18n.Catalog
+	// Health 就绪检查项注册表，其他组件可以注册自己的检查项
+	Health *health.Registry
+
+	TxManager                repo.TxManager
+	UserRepo                 repo.UserRepo
+	LoginTokenRepo           repo.LoginTokenRepo
+	RevokedSessionRepo       repo.RevokedSessionRepo
+	UserService              service.UserService
+	LoginTokenService        service.LoginTokenService
+	SessionRevocationService service.SessionRevocationService
+	UserHandler              *handler.UserHandler
+	HealthHandler            *handler.HealthHandler
+	DocsHandler              *handler.DocsHandler
+
+	// schemaErr 启动时检查表结构的结果
+	schemaErr error
+
+	Echo *echo.Echo
+	// MetricsServer 独立端口的指标服务，未配置 metrics.port 时为 nil
+	MetricsServer *http.Server
+}
+
+// New 根据配置创建应用实例：连接数据库、执行迁移并组装各层依赖. 所有组件的日志输出到 appLogger.
+func New(cfgManager *configs.Manager, appLogger *slog.Logger) (*App, error) {
+	a := &App{
+		Config: cfgManager,
+		Logger: appLogger,
+	}
+
+	if err := a.init(cfgManager.Current()); err != nil {
+		return nil, errors.Join(err, a.Close())
+	}
+
+	return a, nil
+}
+
+// init 按依赖顺序初始化各组件，失败时由调用方释放已创建的资源.
+func (a *App) init(cfg *configs.Config) error {
+	var err error
+
+	// 初始化链路追踪
+	a.Tracing, err = tracing.New(context.Background(), cfg.Tracing)
+	if err != nil {
+		return fmt.Errorf("链路追踪初始化失败: %w", err)
+	}
+
+	// 初始化数据库
+	dbLogLevel, err := database.ParseLogLevel(cfg.Database.LogLevel)
+	if err != nil {
+		return err
+	}
+
+	a.DBLogger = database.NewLogger(a.Logger.With("component", "gorm"), dbLogLevel, cfg.Database.SlowThreshold, cfg.Database.LogParams)
+
+	a.DB, err = database.Open(cfg, a.Logger.With("component", "database"), a.DBLogger)
+	if err != nil {
+		return fmt.Errorf("数据库初始化失败: %w", err)
+	}
+
+	if err := database.UseTracing(a.DB, a.Tracing, cfg.Database.LogParams); err != nil {
+		return fmt.Errorf("数据库链路追踪初始化失败: %w", err)
+	}
+
+	// 执行数据库迁移
+	if err := database.AutoMigrate(a.DB, models...); err != nil {
+		return fmt.Errorf("数据库迁移失败: %w", err)
+	}
+
+	// 表结构只在启动时检查一次，就绪检查返回缓存的结果
+	a.schemaErr = database.CheckMigrations(context.Background(), a.DB, models...)
+	if a.schemaErr != nil {
+		a.Logger.Error("数据库表结构与模型不一致", "error", a.schemaErr)
+	}
+
+	a.Metrics = metrics.New(cfg.GetSessionLifetime())
+	if err := registerDBMetrics(a.Metrics, a.DB, a.DBLogger); err != nil {
+		return fmt.Errorf("注册数据库指标失败: %w", err)
+	}
+
+	a.Config.Subscribe(a.applyDatabaseLogging)
+
+	googleValidator, err := service.NewGoogleTokenValidator(context.Background(), a.Tracing, a.Tracing.Propagator)
+	if err != nil {
+		return err
+	}
+
+	a.Messages, err = i18n.Load()
+	if err != nil {
+		return fmt.Errorf("消息目录加载失败: %w", err)
+	}
+
+	for locale, keys := range a.Messages.Missing() {
+		a.Logger.Warn("消息目录缺少翻译，将使用默认语言", "locale", locale, "keys", keys)
+	}
+
+	// 初始化依赖
+	readYourWrites := database.NewReadYourWrites(cfg.Database.ReadYourWritesTime)
+	a.UserRepo = repo.NewUserRepo(a.DB, cfg.Database.QueryTimeout, readYourWrites)
+	a.LoginTokenRepo = repo.NewLoginTokenRepo(a.DB, cfg.Database.QueryTimeout, readYourWrites)
+	a.RevokedSessionRepo = repo.NewRevokedSessionRepo(a.DB, cfg.Database.QueryTimeout)
+	a.TxManager = repo.NewTxManager(a.DB, cfg.Database.QueryTimeout, readYourWrites)
+
+	serviceLogger := a.Logger.With("component", "service")
+	serviceTracer := a.Tracing.Tracer("go-react-template/pkg/service")
+	a.UserService = service.NewTracedUserService(
+		service.NewUserService(a.UserRepo, a.TxManager, serviceLogger, a.Metrics, googleValidator),
+		serviceTracer,
+	)
+	a.LoginTokenService = service.NewTracedLoginTokenService(
+		service.NewLoginTokenService(a.LoginTokenRepo, a.UserRepo, serviceLogger, cfg.Session.RememberMeTTL),
+		serviceTracer,
+	)
+	a.SessionRevocationService = service.NewTracedSessionRevocationService(
+		service.NewSessionRevocationService(a.RevokedSessionRepo, serviceLogger),
+		serviceTracer,
+	)
+
+	a.Sessions = middleware.NewSessionMiddleware(cfg.Session, cfg.IsProduction(), a.Metrics, a.LoginTokenService, a.SessionRevocationService, a.Logger.With("component", "session"))
+	a.UserHandler = handler.NewUserHandler(a.UserService, a.LoginTokenService, a.Sessions, a.Messages)
+
+	rateLimitStore, err := ratelimit.NewStore(cfg.RateLimit.Backend)
+	if err != nil {
+		return fmt.Errorf("限流初始化失败: %w", err)
+	}
+
+	a.RateLimiter = middleware.NewRateLimiter(a.Config, rateLimitStore, a.Sessions, a.Logger.With("component", "ratelimit"))
+
+	a.Health = health.NewRegistry(healthCheckTimeout, a.Logger.With("component", "health"))
+	a.registerHealthChecks()
+	a.HealthHandler = handler.NewHealthHandler(a.Health, a.Messages)
+
+	spec := api.OpenAPI()
+
+	specJSON, err := spec.JSON()
+	if err != nil {
+		return fmt.Errorf("生成接口文档失败: %w", err)
+	}
+
+	a.DocsHandler = handler.NewDocsHandler(specJSON)
+
+	trustedProxies, err := middleware.ParseTrustedProxies(cfg.Server.TrustedProxies)
+	if err != nil {
+		return fmt.Errorf("解析可信代理地址失败: %w", err)
+	}
+
+	files, err := a.staticFiles(cfg)
+	if err != nil {
+		return err
+	}
+
+	a.Echo = a.newEcho(trustedProxies, files)
+
+	for _, problem := range api.CheckOpenAPI(spec, a.Echo.Routes()) {
+		a.Logger.Warn("接口文档与路由不一致", "problem", problem)
+	}
+	a.MetricsServer = a.newMetricsServer()
+
+	return nil
+}
+
+// registerHealthChecks 注册内置的就绪检查项.
+func (a *App) registerHealthChecks() {
+	a.Health.Register("database", func(ctx context.Context) error {
+		return database.Ping(ctx, a.DB)
+	})
+	a.Health.Register("migrations", func(context.Context) error {
+		return a.schemaErr
+	})
+	a.Health.Register("session_store", a.Sessions.Check)
+}
+
+// registerDBMetrics 注册所有连接池的统计指标和慢查询指标.
+func registerDBMetrics(m *metrics.Metrics, db *gorm.DB, dbLogger *database.Logger) error {
+	pools, err := database.Pools(db)
+	if err != nil {
+		return err
+	}
+
+	for name, pool := range pools {
+		if err := m.RegisterDB(name, pool); err != nil {
+			return err
+		}
+	}
+
+	return m.RegisterSlowQueries(dbLogger.SlowQueries)
+}
+
+// applyDatabaseLogging 配置热加载后更新SQL日志级别和慢查询阈值.
+func (a *App) applyDatabaseLogging(_, updated *configs.Config) {
+	level, err := database.ParseLogLevel(updated.Database.LogLevel)
+	if err != nil {
+		a.Logger.Error("更新SQL日志级别失败", "error", err)
+		return
+	}
+
+	a.DBLogger.SetLevel(level)
+	a.DBLogger.SetSlowThreshold(updated.Database.SlowThreshold)
+}
+
+// Start 启动HTTP服务（以及独立端口的指标服务），阻塞直到服务停止. 通过 Shutdown 正常停止时返回 nil.
+func (a *App) Start() error {
+	if a.MetricsServer != nil {
+		go func() {
+			a.Logger.Info("指标服务启动", "address", a.MetricsServer.Addr)
+
+			if err := a.MetricsServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				a.Logger.Error("指标服务运行失败", "error", err)
+			}
+		}()
+	}
+
+	if err := a.Echo.Start(a.Config.Current().GetServerAddress()); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+
+	return nil
+}
+
+// Shutdown 优雅关闭：先让就绪检查失败并等待 server.shutdown_delay，使负载均衡摘除本实例；
+// 然后停止接收新请求，在 server.shutdown_timeout 内等待处理中的请求完成，超时则强制断开；
+// 最后释放数据库等资源.
+func (a *App) Shutdown(ctx context.Context) error {
+	cfg := a.Config.Current().Server
+
+	a.Health.SetShuttingDown()
+	a.Logger.Info("开始优雅关闭", "delay", cfg.ShutdownDelay, "timeout", cfg.ShutdownTimeout)
+
+	if cfg.ShutdownDelay > 0 {
+		select {
+		case <-time.After(cfg.ShutdownDelay):
+		case <-ctx.Done():
+		}
+	}
+
+	drainCtx, cancel := context.WithTimeout(ctx, cfg.ShutdownTimeout)
+	defer cancel()
+
+	var errs []error
+
+	if err := a.Echo.Shutdown(drainCtx); err != nil {
+		a.Logger.Warn("等待请求处理完成超时，强制断开剩余连接", "error", err)
+		errs = append(errs, fmt.Errorf("HTTP服务关闭失败: %w", err))
+	}
+
+	if a.MetricsServer != nil {
+		if err := a.MetricsServer.Shutdown(drainCtx); err != nil {
+			errs = append(errs, fmt.Errorf("指标服务关闭失败: %w", err))
+		}
+	}
+
+	// 强制关闭未完成的连接并释放资源
+	errs = append(errs, a.Close())
+
+	return errors.Join(errs...)
+}
+
+// Close 释放应用持有的资源，可以在初始化未完成时调用.
+func (a *App) Close() error {
+	var errs []error
+
+	if a.Echo != nil {
+		errs = append(errs, a.Echo.Close())
+	}
+
+	if a.MetricsServer != nil {
+		errs = append(errs, a.MetricsServer.Close())
+	}
+
+	if a.DB != nil {
+		errs = append(errs, database.Close(a.DB))
+	}
+
+	if a.Tracing != nil {
+		errs = append(errs, a.Tracing.Shutdown(context.Background()))
+	}
+
+	return errors.Join(errs...)
+}
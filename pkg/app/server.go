@@ -0,0 +1,137 @@
+package app
+
+import (
+	"context"
+	"log/slog"
+	"net"
+	"net/http"
+	"time"
+
+	"go-react-template/api"
+	"go-react-template/pkg/handler"
+	appmiddleware "go-react-template/pkg/middleware"
+	"go-react-template/pkg/static"
+	"go-react-template/pkg/validation"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/labstack/echo/otelecho"
+)
+
+// metricsPath Prometheus 指标路径.
+const metricsPath = "/metrics"
+
+// newEcho 创建Echo实例并注册中间件和路由. 只采信 trustedProxies 转发的 X-Forwarded-* 请求头，
+// files 为前端静态文件服务，为 nil 时只提供API服务.
+func (a *App) newEcho(trustedProxies []*net.IPNet, files *static.Server) *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = handler.NewErrorHandler(a.Messages, a.Logger.With("component", "handler"))
+	e.Validator = validation.New()
+	e.Binder = &validation.Binder{}
+	e.IPExtractor = appmiddleware.IPExtractor(trustedProxies)
+
+	// 添加中间件
+	e.Use(appmiddleware.TrustedProxies(trustedProxies))
+	e.Use(appmiddleware.RequestID())
+	e.Use(appmiddleware.Locale(a.Messages))
+	e.Use(appmiddleware.SecurityHeaders(a.Config))
+	e.Use(otelecho.Middleware(a.Config.Current().Tracing.ServiceName,
+		otelecho.WithTracerProvider(a.Tracing),
+		otelecho.WithPropagators(a.Tracing.Propagator),
+		otelecho.WithSkipper(isProbePath),
+	))
+	e.Use(a.Metrics.Middleware())
+	e.Use(middleware.RequestLoggerWithConfig(middleware.RequestLoggerConfig{
+		LogMethod:    true,
+		LogURI:       true,
+		LogRoutePath: true,
+		LogStatus:    true,
+		LogLatency:   true,
+		LogRemoteIP:  true,
+		LogError:     true,
+		HandleError:  true,
+		LogValuesFunc: func(c echo.Context, v middleware.RequestLoggerValues) error {
+			a.logRequest(c.Request().Context(), v)
+			return nil
+		},
+	}))
+	e.Use(middleware.Recover())
+	// 启用 Gzip 压缩
+	e.Use(middleware.GzipWithConfig(middleware.GzipConfig{
+		Level: 5, // 压缩级别 1-9，5 是性能和压缩率的平衡
+		Skipper: func(c echo.Context) bool {
+			// 静态文件由静态文件服务返回构建时预压缩的版本
+			return isStaticFile(files, c)
+		},
+	}))
+	e.Use(appmiddleware.CORS(a.Config))
+
+	// 未配置独立端口时，指标与业务共用端口
+	cfg := a.Config.Current()
+	if cfg.Metrics.Enabled && cfg.Metrics.Port == "" {
+		e.GET(metricsPath, echo.WrapHandler(a.Metrics.Handler()))
+	}
+
+	// 设置API路由
+	api.SetupRoutes(e, a.UserHandler, a.HealthHandler, a.DocsHandler, a.Sessions, a.RateLimiter)
+
+	// 设置静态文件服务
+	setupStaticFiles(e, files)
+
+	return e
+}
+
+// isProbePath 判断是否为指标采集或健康探测请求，这类请求不创建链路追踪 span.
+func isProbePath(c echo.Context) bool {
+	switch c.Path() {
+	case metricsPath, "/livez", "/readyz":
+		return true
+	default:
+		return false
+	}
+}
+
+// newMetricsServer 创建独立端口的指标服务，未启用指标或未配置独立端口时返回 nil.
+func (a *App) newMetricsServer() *http.Server {
+	cfg := a.Config.Current()
+	if !cfg.Metrics.Enabled || cfg.Metrics.Port == "" {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(metricsPath, a.Metrics.Handler())
+
+	return &http.Server{
+		Addr:              cfg.GetMetricsAddress(),
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		ErrorLog:          slog.NewLogLogger(a.Logger.With("component", "metrics").Handler(), slog.LevelError),
+	}
+}
+
+// logRequest 输出访问日志：5xx 为错误，4xx 为警告，其余为信息.
+func (a *App) logRequest(ctx context.Context, v middleware.RequestLoggerValues) {
+	level := slog.LevelInfo
+
+	switch {
+	case v.Status >= 500:
+		level = slog.LevelError
+	case v.Status >= 400:
+		level = slog.LevelWarn
+	}
+
+	attrs := []slog.Attr{
+		slog.String("method", v.Method),
+		slog.String("uri", v.URI),
+		slog.String("route", v.RoutePath),
+		slog.Int("status", v.Status),
+		slog.Duration("latency", v.Latency),
+		slog.String("remote_ip", v.RemoteIP),
+	}
+
+	if v.Error != nil {
+		attrs = append(attrs, slog.Any("error", v.Error))
+	}
+
+	a.Logger.LogAttrs(ctx, level, "HTTP请求", attrs...)
+}
@@ -0,0 +1,172 @@
+package app
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+
+	"go-react-template/configs"
+	"go-react-template/pkg/reqctx"
+	"go-react-template/pkg/static"
+	"go-react-template/web"
+
+	"github.com/labstack/echo/v4"
+)
+
+// cspNoncePlaceholder 前端构建时写入 HTML 的 nonce 占位符（web/vite.config.ts 中的 html.cspNonce），
+// 返回页面时替换为本次请求的 CSP nonce.
+const cspNoncePlaceholder = "__CSP_NONCE__"
+
+// indexHTML SPA 入口页面.
+const indexHTML = "index.html"
+
+// errNoStaticFiles 生产模式下没有可用的前端资源.
+var errNoStaticFiles = errors.New("没有可用的前端资源，请使用 -tags embed 构建或配置 server.static_dir")
+
+// staticFiles 返回前端静态文件服务：配置了 server.static_dir 时使用该目录，否则使用嵌入的构建产物.
+//
+// 资源中缺少 index.html 时返回错误，避免启动一个没有页面的服务. 两者都没有时，生产模式返回错误；
+// 开发模式返回 nil，只提供API服务（页面由 Vite 开发服务器提供）.
+func (a *App) staticFiles(cfg *configs.Config) (*static.Server, error) {
+	var (
+		fsys   fs.FS
+		source string
+	)
+
+	if cfg.Server.StaticDir != "" {
+		fsys = os.DirFS(cfg.Server.StaticDir)
+		source = cfg.Server.StaticDir
+	} else {
+		dist, err := web.Dist()
+		if err != nil {
+			return nil, fmt.Errorf("读取嵌入的前端资源失败: %w", err)
+		}
+
+		if dist == nil {
+			if cfg.IsProduction() {
+				return nil, errNoStaticFiles
+			}
+
+			a.Logger.Warn("未嵌入前端资源且未配置 server.static_dir，只提供API服务")
+
+			return nil, nil //nolint:nilnil // 开发模式允许没有前端资源
+		}
+
+		fsys = dist
+		source = "嵌入资源"
+	}
+
+	if _, err := fs.Stat(fsys, indexHTML); err != nil {
+		return nil, fmt.Errorf("前端资源 %s 中缺少 %s: %w", source, indexHTML, err)
+	}
+
+	files, err := static.New(fsys)
+	if err != nil {
+		return nil, err
+	}
+
+	a.Logger.Info("前端静态资源", "source", source)
+
+	return files, nil
+}
+
+// setupStaticFiles 设置静态文件服务，files 为 nil 时不提供页面.
+func setupStaticFiles(e *echo.Echo, files *static.Server) {
+	if files == nil {
+		return
+	}
+
+	// 服务带有哈希的静态资源文件（长期缓存）
+	e.GET("/assets/*", func(c echo.Context) error {
+		name, ok := files.Lookup(c.Request().URL.Path)
+		if !ok {
+			return echo.NewHTTPError(http.StatusNotFound, "File not found")
+		}
+		// 设置强缓存：1年，因为文件名包含哈希值
+		c.Response().Header().Set("Cache-Control", "public, max-age=31536000, immutable")
+
+		return serveFile(c, files, name)
+	})
+
+	// 服务 favicon（短期缓存）
+	e.GET("/favicon.ico", func(c echo.Context) error {
+		c.Response().Header().Set("Cache-Control", "public, max-age=86400") // 1天
+		return serveFile(c, files, "favicon.ico")
+	})
+
+	// 服务网站图标 SVG（长期缓存）
+	e.GET("/vite.svg", func(c echo.Context) error {
+		c.Response().Header().Set("Cache-Control", "public, max-age=604800") // 7天
+		return serveFile(c, files, "vite.svg")
+	})
+
+	// 处理SPA路由，所有非API请求都返回index.html
+	e.GET("/*", func(c echo.Context) error {
+		urlPath := c.Request().URL.Path
+
+		// 如果是API请求，返回404
+		if strings.HasPrefix(urlPath, "/api") {
+			return echo.NewHTTPError(http.StatusNotFound, "API endpoint not found")
+		}
+
+		// 检查请求的文件是否存在
+		if name, ok := files.Lookup(urlPath); ok {
+			if path.Ext(name) == ".html" {
+				return serveHTML(c, files, name)
+			}
+
+			return serveFile(c, files, name)
+		}
+
+		// 文件不存在，返回index.html（SPA路由）
+		return serveHTML(c, files, indexHTML)
+	})
+}
+
+// isStaticFile 判断请求是否由静态文件服务直接返回文件. 这类文件有构建时预压缩的版本，不需要再实时压缩；
+// HTML 页面每次都要替换 nonce，仍然实时压缩.
+func isStaticFile(files *static.Server, c echo.Context) bool {
+	if files == nil {
+		return false
+	}
+
+	urlPath := c.Request().URL.Path
+	if strings.HasPrefix(urlPath, "/api") {
+		return false
+	}
+
+	name, ok := files.Lookup(urlPath)
+
+	return ok && path.Ext(name) != ".html"
+}
+
+// serveFile 返回静态文件，文件不存在时返回404.
+func serveFile(c echo.Context, files *static.Server, name string) error {
+	if err := files.ServeFile(c.Response(), c.Request(), name); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return echo.NewHTTPError(http.StatusNotFound, "File not found")
+		}
+
+		return fmt.Errorf("读取静态文件失败: %w", err)
+	}
+
+	return nil
+}
+
+// serveHTML 返回 HTML 页面，并将 nonce 占位符替换为本次请求的 CSP nonce. 每个请求的内容不同，要求浏览器每次重新获取.
+func serveHTML(c echo.Context, files *static.Server, name string) error {
+	data, err := files.ReadFile(name)
+	if err != nil {
+		return echo.NewHTTPError(http.StatusNotFound, "File not found")
+	}
+
+	nonce := reqctx.CSPNonce(c.Request().Context())
+	c.Response().Header().Set("Cache-Control", "no-cache")
+
+	return c.HTMLBlob(http.StatusOK, bytes.ReplaceAll(data, []byte(cspNoncePlaceholder), []byte(nonce)))
+}
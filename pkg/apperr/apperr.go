@@ -0,0 +1,84 @@
+// Package apperr 带稳定错误码的应用错误，由HTTP层统一转换为状态码和本地化消息
+package apperr
+
+import (
+	"errors"
+	"strings"
+)
+
+// Kind 错误类别，决定返回的HTTP状态码.
+type Kind int
+
+// 错误类别.
+const (
+	KindInternal        Kind = iota // 服务器内部错误
+	KindInvalid                     // 请求参数不合法
+	KindUnauthenticated             // 未登录或登录已失效
+	KindForbidden                   // 无权访问
+	KindNotFound                    // 资源不存在
+	KindConflict                    // 与现有数据冲突
+)
+
+// Error 应用错误. Code 为稳定的字符串错误码，前端据此区分错误，对应的消息从消息目录中读取.
+type Error struct {
+	Kind   Kind
+	Code   string
+	Fields []FieldError // 字段级校验错误，仅 KindInvalid 使用
+}
+
+// FieldError 单个字段的校验错误. Code 为校验规则（如 required、email），Param 为规则参数.
+type FieldError struct {
+	Field string
+	Code  string
+	Param string
+}
+
+// New 创建应用错误.
+func New(kind Kind, code string) *Error {
+	return &Error{Kind: kind, Code: code}
+}
+
+// Validation 创建带字段错误的参数校验错误.
+func Validation(fields ...FieldError) *Error {
+	return &Error{Kind: KindInvalid, Code: ErrValidation.Code, Fields: fields}
+}
+
+// Error 返回错误码，校验错误附带字段和规则，便于在日志中定位.
+func (e *Error) Error() string {
+	if len(e.Fields) == 0 {
+		return e.Code
+	}
+
+	fields := make([]string, len(e.Fields))
+	for i, fe := range e.Fields {
+		fields[i] = fe.Field + ":" + fe.Code
+	}
+
+	return e.Code + " (" + strings.Join(fields, ", ") + ")"
+}
+
+// Is 错误码相同即视为同一错误，使 errors.Is(err, ErrValidation) 对带字段的校验错误也成立.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// As 从错误链中取出应用错误.
+func As(err error) (*Error, bool) {
+	var appErr *Error
+	if errors.As(err, &appErr) {
+		return appErr, true
+	}
+
+	return nil, false
+}
+
+// 通用错误.
+var (
+	ErrInternal        = New(KindInternal, "internal_error")
+	ErrBadRequest      = New(KindInvalid, "bad_request")
+	ErrValidation      = New(KindInvalid, "validation_failed")
+	ErrUnauthenticated = New(KindUnauthenticated, "unauthenticated")
+	ErrForbidden       = New(KindForbidden, "forbidden")
+	ErrNotFound        = New(KindNotFound, "not_found")
+)
@@ -2,8 +2,10 @@
 package database
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"log/slog"
 
 	"go-react-template/configs"
 
@@ -12,63 +14,153 @@ import (
 	"gorm.io/driver/sqlite"
 	"gorm.io/gorm"
 	"gorm.io/gorm/logger"
+	"gorm.io/plugin/dbresolver"
 )
 
-var DB *gorm.DB
-
-// Init 初始化数据库连接.
-func Init() error {
-	if configs.AppConfig == nil {
-		return fmt.Errorf("配置未初始化，请先调用 configs.Init()")
+// Open 根据配置打开数据库连接，连接信息输出到 log，SQL日志输出到 gormLogger；配置了只读副本时读请求由 dbresolver 路由到副本.
+func Open(cfg *configs.Config, log *slog.Logger, gormLogger logger.Interface) (*gorm.DB, error) {
+	if cfg == nil {
+		return nil, fmt.Errorf("配置未初始化，请先调用 configs.Init()")
 	}
 
-	var dialector gorm.Dialector
-
-	dsn := configs.AppConfig.GetDatabaseDSN()
+	dsn := cfg.GetDatabaseDSN()
 
 	// 根据配置选择数据库驱动
-	switch configs.AppConfig.Database.Driver {
+	dialector, err := dialectorFor(cfg.Database.Driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	switch cfg.Database.Driver {
 	case "sqlite":
-		dialector = sqlite.Open(dsn)
-		log.Printf("使用 SQLite 数据库: %s", dsn)
+		log.Info("使用 SQLite 数据库", "path", dsn)
 	case "mysql":
-		dialector = mysql.Open(dsn)
-
-		log.Printf("使用 MySQL 数据库: %s:%s/%s",
-			configs.AppConfig.Database.Host,
-			configs.AppConfig.Database.Port,
-			configs.AppConfig.Database.DBName)
+		log.Info("使用 MySQL 数据库",
+			"host", cfg.Database.Host,
+			"port", cfg.Database.Port,
+			"dbname", cfg.Database.DBName)
 	case "postgres":
-		dialector = postgres.Open(dsn)
-
-		log.Printf("使用 PostgreSQL 数据库: %s:%s/%s",
-			configs.AppConfig.Database.Host,
-			configs.AppConfig.Database.Port,
-			configs.AppConfig.Database.DBName)
-	default:
-		return fmt.Errorf("不支持的数据库驱动: %s", configs.AppConfig.Database.Driver)
+		log.Info("使用 PostgreSQL 数据库",
+			"host", cfg.Database.Host,
+			"port", cfg.Database.Port,
+			"dbname", cfg.Database.DBName)
 	}
 
-	var err error
-
-	DB, err = gorm.Open(dialector, &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+	db, err := gorm.Open(dialector, &gorm.Config{
+		Logger: gormLogger,
 	})
 	if err != nil {
-		return fmt.Errorf("数据库连接失败: %w", err)
+		return nil, fmt.Errorf("数据库连接失败: %w", err)
+	}
+
+	// 配置主库连接池
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库连接池失败: %w", err)
 	}
 
-	log.Println("数据库连接成功")
+	sqlDB.SetMaxOpenConns(cfg.Database.MaxOpenConns)
+	sqlDB.SetMaxIdleConns(cfg.Database.MaxIdleConns)
+	sqlDB.SetConnMaxLifetime(cfg.Database.ConnMaxLifetime)
+	sqlDB.SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
+
+	if err := useReplicas(db, cfg, log); err != nil {
+		return nil, fmt.Errorf("只读副本配置失败: %w", err)
+	}
+
+	log.Info("数据库连接成功")
+
+	return db, nil
+}
+
+// useReplicas 注册只读副本，未配置副本时不做任何处理.
+func useReplicas(db *gorm.DB, cfg *configs.Config, log *slog.Logger) error {
+	dsns := cfg.GetReplicaDSNs()
+	if len(dsns) == 0 {
+		return nil
+	}
+
+	replicas := make([]gorm.Dialector, 0, len(dsns))
+
+	for _, dsn := range dsns {
+		dialector, err := dialectorFor(cfg.Database.Driver, dsn)
+		if err != nil {
+			return err
+		}
+
+		replicas = append(replicas, dialector)
+	}
+
+	resolver := dbresolver.Register(dbresolver.Config{
+		Replicas: replicas,
+		Policy:   dbresolver.RandomPolicy{},
+	}).
+		SetMaxOpenConns(cfg.Database.MaxOpenConns).
+		SetMaxIdleConns(cfg.Database.MaxIdleConns).
+		SetConnMaxLifetime(cfg.Database.ConnMaxLifetime).
+		SetConnMaxIdleTime(cfg.Database.ConnMaxIdleTime)
+
+	if err := db.Use(resolver); err != nil {
+		return err
+	}
+
+	log.Info("已启用只读副本", "count", len(replicas), "hosts", cfg.Database.ReplicaHosts)
 
 	return nil
 }
 
+// dialectorFor 根据驱动名称创建GORM方言.
+func dialectorFor(driver, dsn string) (gorm.Dialector, error) {
+	switch driver {
+	case "sqlite":
+		return sqlite.Open(dsn), nil
+	case "mysql":
+		return mysql.Open(dsn), nil
+	case "postgres":
+		return postgres.Open(dsn), nil
+	default:
+		return nil, fmt.Errorf("不支持的数据库驱动: %s", driver)
+	}
+}
+
 // AutoMigrate 执行数据库迁移.
-func AutoMigrate(models ...interface{}) error {
-	return DB.AutoMigrate(models...)
+func AutoMigrate(db *gorm.DB, models ...interface{}) error {
+	return db.AutoMigrate(models...)
 }
 
-// GetDB 获取数据库实例.
-func GetDB() *gorm.DB {
-	return DB
+// Pools 返回主库和只读副本的连接池，键为 primary、replica-0、replica-1 等.
+func Pools(db *gorm.DB) (map[string]*sql.DB, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+
+	pools := map[string]*sql.DB{"primary": sqlDB}
+
+	if resolver, ok := db.Config.Plugins[(&dbresolver.DBResolver{}).Name()].(*dbresolver.DBResolver); ok {
+		err = resolver.Call(func(pool gorm.ConnPool) error {
+			if replica, ok := pool.(*sql.DB); ok && replica != sqlDB {
+				pools[fmt.Sprintf("replica-%d", len(pools)-1)] = replica
+			}
+
+			return nil
+		})
+	}
+
+	return pools, err
+}
+
+// Close 关闭数据库连接，包括只读副本的连接池.
+func Close(db *gorm.DB) error {
+	pools, err := Pools(db)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, 0, len(pools))
+	for _, pool := range pools {
+		errs = append(errs, pool.Close())
+	}
+
+	return errors.Join(errs...)
 }
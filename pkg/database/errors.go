@@ -0,0 +1,51 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+	"gorm.io/gorm"
+)
+
+// safeErrors 信息为固定文本、可以直接输出的标准错误.
+var safeErrors = []error{
+	context.Canceled,
+	context.DeadlineExceeded,
+	sql.ErrNoRows,
+	sql.ErrTxDone,
+	sql.ErrConnDone,
+	gorm.ErrRecordNotFound,
+}
+
+// RedactError 返回不含数据的错误描述，用于未开启参数输出时的日志和 span.
+// 驱动错误信息可能带有出错的取值（例如唯一约束冲突时的邮箱），这里只保留 SQLSTATE、错误码或错误类型.
+func RedactError(err error) string {
+	var (
+		pgErr     *pgconn.PgError
+		mysqlErr  *mysql.MySQLError
+		sqliteErr sqlite3.Error
+	)
+
+	switch {
+	case errors.As(err, &pgErr):
+		return "postgres: SQLSTATE " + pgErr.Code
+	case errors.As(err, &mysqlErr):
+		return fmt.Sprintf("mysql: Error %d (SQLSTATE %s)", mysqlErr.Number, string(mysqlErr.SQLState[:]))
+	case errors.As(err, &sqliteErr):
+		return fmt.Sprintf("sqlite: %s (%d)", sqliteErr.Code.Error(), sqliteErr.ExtendedCode)
+	}
+
+	// 标准错误的信息是固定文本，不含数据，但外层包装的信息可能含有数据，只输出标准错误本身
+	for _, target := range safeErrors {
+		if errors.Is(err, target) {
+			return target.Error()
+		}
+	}
+
+	return fmt.Sprintf("%T", err)
+}
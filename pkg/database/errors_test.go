@@ -0,0 +1,65 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+func TestRedactError(t *testing.T) {
+	const secret = "alice@example.com"
+
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{
+			name: "PostgreSQL",
+			err:  &pgconn.PgError{Code: "23505", Message: "duplicate key", Detail: "Key (email)=(" + secret + ") already exists."},
+			want: "postgres: SQLSTATE 23505",
+		},
+		{
+			name: "MySQL",
+			err:  &mysql.MySQLError{Number: 1062, SQLState: [5]byte{'2', '3', '0', '0', '0'}, Message: "Duplicate entry '" + secret + "' for key 'users.idx_users_email'"},
+			want: "mysql: Error 1062 (SQLSTATE 23000)",
+		},
+		{
+			name: "SQLite",
+			err:  sqlite3.Error{Code: sqlite3.ErrConstraint, ExtendedCode: sqlite3.ErrConstraintUnique},
+			want: "sqlite: constraint failed (2067)",
+		},
+		{
+			name: "包装后的驱动错误",
+			err:  fmt.Errorf("创建用户 %s: %w", secret, &pgconn.PgError{Code: "23505"}),
+			want: "postgres: SQLSTATE 23505",
+		},
+		{
+			name: "标准错误",
+			err:  fmt.Errorf("查询 %s: %w", secret, context.DeadlineExceeded),
+			want: "context deadline exceeded",
+		},
+		{
+			name: "未知错误",
+			err:  fmt.Errorf("值 %s 无效", secret),
+			want: "*errors.errorString",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := RedactError(tt.err); got != tt.want {
+				t.Fatalf("RedactError() = %q, want %q", got, tt.want)
+			}
+
+			if strings.Contains(RedactError(tt.err), secret) {
+				t.Fatal("脱敏后的错误不应包含数据")
+			}
+		})
+	}
+}
@@ -0,0 +1,57 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// Ping 检查主库和所有只读副本是否可以连接.
+func Ping(ctx context.Context, db *gorm.DB) error {
+	pools, err := Pools(db)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+
+	for name, pool := range pools {
+		if err := pool.PingContext(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("%s: %w", name, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// CheckMigrations 检查模型对应的表和列是否都已存在，用于发现迁移未执行或执行不完整.
+func CheckMigrations(ctx context.Context, db *gorm.DB, models ...interface{}) error {
+	db = db.WithContext(ctx)
+	migrator := db.Migrator()
+
+	for _, model := range models {
+		stmt := &gorm.Statement{DB: db}
+		if err := stmt.Parse(model); err != nil {
+			return fmt.Errorf("解析模型失败: %w", err)
+		}
+
+		table := stmt.Schema.Table
+		if !migrator.HasTable(model) {
+			return fmt.Errorf("数据表 %s 不存在", table)
+		}
+
+		for _, field := range stmt.Schema.Fields {
+			if field.DBName == "" {
+				continue
+			}
+
+			if !migrator.HasColumn(model, field.DBName) {
+				return fmt.Errorf("数据表 %s 缺少列 %s", table, field.DBName)
+			}
+		}
+	}
+
+	return nil
+}
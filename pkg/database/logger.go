@@ -0,0 +1,248 @@
+package database
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// repoPackage 仓储层的包路径，慢查询按其中的调用方法统计.
+const repoPackage = "go-react-template/pkg/repo."
+
+// maxCallerDepth 查找仓储调用方法时最多回溯的栈帧数.
+const maxCallerDepth = 32
+
+// ParseLogLevel 将配置中的日志级别转换为GORM日志级别.
+func ParseLogLevel(level string) (logger.LogLevel, error) {
+	switch level {
+	case "silent":
+		return logger.Silent, nil
+	case "error":
+		return logger.Error, nil
+	case "warn":
+		return logger.Warn, nil
+	case "info":
+		return logger.Info, nil
+	default:
+		return 0, fmt.Errorf("无效的SQL日志级别: %s", level)
+	}
+}
+
+// Logger 基于 slog 的GORM日志实现：按级别输出SQL，统计慢查询，并默认隐藏SQL绑定参数.
+// 级别和慢查询阈值可以在运行时修改.
+type Logger struct {
+	log       *slog.Logger
+	logParams bool
+
+	// level 为 nil 时使用共享的级别，LogMode 返回的副本持有自己的级别
+	level  *atomic.Int32
+	shared *loggerState
+}
+
+// loggerState LogMode 派生出的日志实例之间共享的状态.
+type loggerState struct {
+	level         atomic.Int32
+	slowThreshold atomic.Int64
+
+	mu          sync.Mutex
+	slowQueries map[string]uint64
+}
+
+// NewLogger 创建GORM日志实例. logParams 为 false 时SQL中的参数以占位符输出，错误只输出错误码.
+func NewLogger(log *slog.Logger, level logger.LogLevel, slowThreshold time.Duration, logParams bool) *Logger {
+	l := &Logger{
+		log:       log,
+		logParams: logParams,
+		shared: &loggerState{
+			slowQueries: make(map[string]uint64),
+		},
+	}
+
+	l.SetLevel(level)
+	l.SetSlowThreshold(slowThreshold)
+
+	return l
+}
+
+// SetLevel 修改日志级别.
+func (l *Logger) SetLevel(level logger.LogLevel) {
+	l.shared.level.Store(int32(level))
+}
+
+// SetSlowThreshold 修改慢查询阈值，0 表示不检测.
+func (l *Logger) SetSlowThreshold(threshold time.Duration) {
+	l.shared.slowThreshold.Store(int64(threshold))
+}
+
+// SlowQueries 返回各仓储方法的慢查询次数.
+func (l *Logger) SlowQueries() map[string]uint64 {
+	l.shared.mu.Lock()
+	defer l.shared.mu.Unlock()
+
+	counts := make(map[string]uint64, len(l.shared.slowQueries))
+	for caller, n := range l.shared.slowQueries {
+		counts[caller] = n
+	}
+
+	return counts
+}
+
+// LogMode 返回使用指定级别的日志实例，慢查询统计与原实例共享.
+func (l *Logger) LogMode(level logger.LogLevel) logger.Interface {
+	derived := *l
+	derived.level = new(atomic.Int32)
+	derived.level.Store(int32(level))
+
+	return &derived
+}
+
+// Info 输出信息日志.
+func (l *Logger) Info(ctx context.Context, msg string, data ...interface{}) {
+	if l.currentLevel() >= logger.Info {
+		l.log.InfoContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Warn 输出警告日志.
+func (l *Logger) Warn(ctx context.Context, msg string, data ...interface{}) {
+	if l.currentLevel() >= logger.Warn {
+		l.log.WarnContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Error 输出错误日志.
+func (l *Logger) Error(ctx context.Context, msg string, data ...interface{}) {
+	if l.currentLevel() >= logger.Error {
+		l.log.ErrorContext(ctx, fmt.Sprintf(msg, data...))
+	}
+}
+
+// Trace 在每条SQL执行后调用：记录执行失败的SQL、慢查询以及 info 级别下的全部SQL.
+func (l *Logger) Trace(ctx context.Context, begin time.Time, fc func() (sql string, rowsAffected int64), err error) {
+	level := l.currentLevel()
+	elapsed := time.Since(begin)
+	threshold := time.Duration(l.shared.slowThreshold.Load())
+	slow := threshold > 0 && elapsed > threshold
+
+	// 慢查询无论日志级别如何都计数
+	var caller string
+	if slow {
+		caller = repoCaller()
+		l.countSlow(caller)
+	}
+
+	if level <= logger.Silent {
+		return
+	}
+
+	switch {
+	case err != nil && level >= logger.Error && !errors.Is(err, gorm.ErrRecordNotFound):
+		sql, rows := fc()
+		l.log.ErrorContext(ctx, "SQL执行失败", l.attrs(sql, rows, elapsed, caller, err)...)
+	case slow && level >= logger.Warn:
+		sql, rows := fc()
+		l.log.WarnContext(ctx, "慢查询", append(l.attrs(sql, rows, elapsed, caller, nil), slog.Duration("threshold", threshold))...)
+	case level >= logger.Info:
+		sql, rows := fc()
+		l.log.InfoContext(ctx, "SQL", l.attrs(sql, rows, elapsed, caller, nil)...)
+	}
+}
+
+// ParamsFilter 未开启参数输出时去掉绑定参数，GORM 会在SQL中保留占位符.
+func (l *Logger) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.logParams {
+		return sql, params
+	}
+
+	return sql, nil
+}
+
+// currentLevel 返回当前生效的日志级别.
+func (l *Logger) currentLevel() logger.LogLevel {
+	if l.level != nil {
+		return logger.LogLevel(l.level.Load())
+	}
+
+	return logger.LogLevel(l.shared.level.Load())
+}
+
+// countSlow 累加调用方法的慢查询次数.
+func (l *Logger) countSlow(caller string) {
+	l.shared.mu.Lock()
+	l.shared.slowQueries[caller]++
+	l.shared.mu.Unlock()
+}
+
+// attrs 生成SQL日志的结构化字段.
+func (l *Logger) attrs(sql string, rows int64, elapsed time.Duration, caller string, err error) []any {
+	if caller == "" {
+		caller = repoCaller()
+	}
+
+	attrs := []any{
+		slog.String("sql", sql),
+		slog.Duration("elapsed", elapsed),
+		slog.String("caller", caller),
+	}
+
+	if rows >= 0 {
+		attrs = append(attrs, slog.Int64("rows", rows))
+	}
+
+	if err != nil {
+		if l.logParams {
+			attrs = append(attrs, slog.Any("error", err))
+		} else {
+			attrs = append(attrs, slog.String("error", RedactError(err)))
+		}
+	}
+
+	return attrs
+}
+
+// repoCaller 返回发起SQL的仓储方法（例如 userRepo.GetByEmail），
+// 不是由仓储发起时返回GORM和本包之外第一个调用处的文件和行号.
+func repoCaller() string {
+	pcs := make([]uintptr, maxCallerDepth)
+	frames := runtime.CallersFrames(pcs[:runtime.Callers(3, pcs)])
+
+	var caller, fallback string
+
+	// 仓储方法之间可能相互调用（例如 GetByEmail 调用 first），取最外层的仓储方法
+	for {
+		frame, more := frames.Next()
+
+		if name, ok := strings.CutPrefix(frame.Function, repoPackage); ok {
+			caller = strings.NewReplacer("(*", "", ")", "").Replace(name)
+		} else if caller != "" {
+			break
+		} else if fallback == "" && !isInternalFrame(frame.Function) {
+			fallback = frame.File + ":" + strconv.Itoa(frame.Line)
+		}
+
+		if !more {
+			break
+		}
+	}
+
+	if caller == "" {
+		return fallback
+	}
+
+	return caller
+}
+
+// isInternalFrame 判断栈帧是否属于GORM或本包，查找调用处时跳过.
+func isInternalFrame(function string) bool {
+	return strings.HasPrefix(function, "gorm.io/") || strings.HasPrefix(function, "go-react-template/pkg/database.")
+}
@@ -0,0 +1,98 @@
+package database
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go-react-template/pkg/reqctx"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// readYourWritesSweepSize 记录数超过该值时清理过期记录.
+const readYourWritesSweepSize = 1024
+
+// ReadYourWrites 记录用户最近一次写入的时间，窗口期内该用户的读请求走主库，
+// 避免副本复制延迟导致用户读不到自己刚写入的数据. 未登录请求按客户端IP粘滞，例如注册后立即登录.
+type ReadYourWrites struct {
+	window time.Duration
+
+	mu        sync.Mutex
+	lastWrite map[string]time.Time
+}
+
+// NewReadYourWrites 创建读写粘滞记录器，window 为 0 时不做粘滞.
+func NewReadYourWrites(window time.Duration) *ReadYourWrites {
+	return &ReadYourWrites{
+		window:    window,
+		lastWrite: make(map[string]time.Time),
+	}
+}
+
+// MarkWrite 记录当前请求用户的一次写入.
+func (r *ReadYourWrites) MarkWrite(ctx context.Context) {
+	key := stickyKey(ctx)
+	if r == nil || r.window <= 0 || key == "" {
+		return
+	}
+
+	now := time.Now()
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.lastWrite[key] = now
+
+	if len(r.lastWrite) > readYourWritesSweepSize {
+		for k, at := range r.lastWrite {
+			if now.Sub(at) > r.window {
+				delete(r.lastWrite, k)
+			}
+		}
+	}
+}
+
+// primaryKey context中标记读请求必须走主库的键.
+type primaryKey struct{}
+
+// WithPrimary 返回要求读请求都走主库的 context，用于根据读到的数据轮换、吊销凭据等不能容忍复制延迟的场景.
+func WithPrimary(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryKey{}, true)
+}
+
+// Reader 返回用于读取的数据库会话：ctx 要求走主库或当前用户处于粘滞窗口内时强制走主库，否则由 dbresolver 路由到副本.
+func (r *ReadYourWrites) Reader(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if primary, _ := ctx.Value(primaryKey{}).(bool); primary { //nolint:errcheck
+		return db.Clauses(dbresolver.Write)
+	}
+
+	key := stickyKey(ctx)
+	if r == nil || r.window <= 0 || key == "" {
+		return db
+	}
+
+	r.mu.Lock()
+	at, ok := r.lastWrite[key]
+	r.mu.Unlock()
+
+	if ok && time.Since(at) <= r.window {
+		return db.Clauses(dbresolver.Write)
+	}
+
+	return db
+}
+
+// stickyKey 返回粘滞记录的键：已登录请求按用户ID，未登录请求按客户端IP，都没有时不做粘滞.
+func stickyKey(ctx context.Context) string {
+	if userID := reqctx.UserID(ctx); userID != "" {
+		return "user:" + userID
+	}
+
+	if ip := reqctx.ClientIP(ctx); ip != "" {
+		return "ip:" + ip
+	}
+
+	return ""
+}
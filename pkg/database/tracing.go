@@ -0,0 +1,102 @@
+package database
+
+import (
+	"errors"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+// tracingSpanKey 在 gorm 实例中保存当前 span 的键.
+const tracingSpanKey = "app:tracing:span"
+
+// tracingPlugin 为每条SQL创建 span 的 GORM 插件.
+type tracingPlugin struct {
+	tracer     trace.Tracer
+	withParams bool
+}
+
+// UseTracing 为每条SQL创建 span. withParams 为 false 时 span 中的SQL以占位符代替绑定参数，错误只记录错误码.
+func UseTracing(db *gorm.DB, tp trace.TracerProvider, withParams bool) error {
+	return db.Use(&tracingPlugin{
+		tracer:     tp.Tracer("go-react-template/pkg/database"),
+		withParams: withParams,
+	})
+}
+
+// Name 实现 gorm.Plugin.
+func (p *tracingPlugin) Name() string {
+	return "app:tracing"
+}
+
+// Initialize 在各类操作前后注册回调.
+func (p *tracingPlugin) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+
+	return errors.Join(
+		cb.Create().Before("gorm:create").Register("app:tracing:before_create", p.before("create")),
+		cb.Create().After("gorm:create").Register("app:tracing:after_create", p.after),
+		cb.Query().Before("gorm:query").Register("app:tracing:before_query", p.before("query")),
+		cb.Query().After("gorm:query").Register("app:tracing:after_query", p.after),
+		cb.Update().Before("gorm:update").Register("app:tracing:before_update", p.before("update")),
+		cb.Update().After("gorm:update").Register("app:tracing:after_update", p.after),
+		cb.Delete().Before("gorm:delete").Register("app:tracing:before_delete", p.before("delete")),
+		cb.Delete().After("gorm:delete").Register("app:tracing:after_delete", p.after),
+		cb.Row().Before("gorm:row").Register("app:tracing:before_row", p.before("row")),
+		cb.Row().After("gorm:row").Register("app:tracing:after_row", p.after),
+		cb.Raw().Before("gorm:raw").Register("app:tracing:before_raw", p.before("raw")),
+		cb.Raw().After("gorm:raw").Register("app:tracing:after_raw", p.after),
+	)
+}
+
+// before 开始 span，span 名称为 "gorm.<操作>".
+func (p *tracingPlugin) before(operation string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		_, span := p.tracer.Start(db.Statement.Context, "gorm."+operation,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(
+				attribute.String("db.system.name", db.Dialector.Name()),
+				attribute.String("db.operation.name", operation),
+			),
+		)
+
+		db.InstanceSet(tracingSpanKey, span)
+	}
+}
+
+// after 记录SQL、影响行数和错误并结束 span.
+func (p *tracingPlugin) after(db *gorm.DB) {
+	value, ok := db.InstanceGet(tracingSpanKey)
+	if !ok {
+		return
+	}
+
+	span, ok := value.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	sql := db.Statement.SQL.String()
+	if p.withParams {
+		sql = db.Dialector.Explain(sql, db.Statement.Vars...)
+	}
+
+	span.SetAttributes(
+		attribute.String("db.query.text", sql),
+		attribute.String("db.collection.name", db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.Statement.RowsAffected),
+	)
+
+	if db.Error != nil && !errors.Is(db.Error, gorm.ErrRecordNotFound) {
+		err := db.Error
+		if !p.withParams {
+			err = errors.New(RedactError(err))
+		}
+
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
@@ -0,0 +1,13 @@
+// 加载本服务的 OpenAPI 文档，替换 swagger-ui 默认的示例文档
+window.onload = function () {
+  window.ui = SwaggerUIBundle({
+    url: "../openapi.json",
+    dom_id: "#swagger-ui",
+    deepLinking: true,
+    // 接口使用 Cookie 会话认证，调试时携带 Cookie
+    withCredentials: true,
+    presets: [SwaggerUIBundle.presets.apis, SwaggerUIStandalonePreset],
+    plugins: [SwaggerUIBundle.plugins.DownloadUrl],
+    layout: "StandaloneLayout",
+  });
+};
@@ -0,0 +1,50 @@
+package handler
+
+import (
+	_ "embed"
+	"net/http"
+	"path"
+
+	swaggerFiles "github.com/swaggo/files/v2"
+
+	"github.com/labstack/echo/v4"
+)
+
+// swaggerInitializer 替换 swagger-ui 自带的初始化脚本，加载本服务的文档.
+//
+//go:embed docs/swagger-initializer.js
+var swaggerInitializer []byte
+
+// DocsHandler 接口文档处理器，提供 OpenAPI 文档和内嵌的 swagger-ui.
+type DocsHandler struct {
+	spec []byte
+}
+
+// NewDocsHandler 创建接口文档处理器，spec 为生成好的 OpenAPI JSON 文档.
+func NewDocsHandler(spec []byte) *DocsHandler {
+	return &DocsHandler{spec: spec}
+}
+
+// GET /api/openapi.json.
+func (h *DocsHandler) Spec(c echo.Context) error {
+	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, h.spec)
+}
+
+// GET /api/docs 重定向到 /api/docs/，使页面中的相对路径指向文档目录.
+func (h *DocsHandler) Redirect(c echo.Context) error {
+	return c.Redirect(http.StatusMovedPermanently, c.Request().URL.Path+"/")
+}
+
+// GET /api/docs/* swagger-ui 页面和静态资源.
+func (h *DocsHandler) UI(c echo.Context) error {
+	name := path.Clean("/" + c.Param("*"))[1:]
+
+	switch name {
+	case "":
+		name = "index.html"
+	case "swagger-initializer.js":
+		return c.Blob(http.StatusOK, "text/javascript; charset=utf-8", swaggerInitializer)
+	}
+
+	return echo.StaticFileHandler(name, swaggerFiles.FS)(c)
+}
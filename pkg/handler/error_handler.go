@@ -0,0 +1,136 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+
+	"go-react-template/pkg/apperr"
+	"go-react-template/pkg/i18n"
+
+	"github.com/labstack/echo/v4"
+)
+
+// kindStatus 应用错误类别对应的HTTP状态码.
+var kindStatus = map[apperr.Kind]int{
+	apperr.KindInternal:        http.StatusInternalServerError,
+	apperr.KindInvalid:         http.StatusBadRequest,
+	apperr.KindUnauthenticated: http.StatusUnauthorized,
+	apperr.KindForbidden:       http.StatusForbidden,
+	apperr.KindNotFound:        http.StatusNotFound,
+	apperr.KindConflict:        http.StatusConflict,
+}
+
+// httpStatusCodes Echo 路由和内置中间件返回的HTTP错误对应的错误码.
+var httpStatusCodes = map[int]string{
+	http.StatusBadRequest:            apperr.ErrBadRequest.Code,
+	http.StatusUnauthorized:          apperr.ErrUnauthenticated.Code,
+	http.StatusForbidden:             apperr.ErrForbidden.Code,
+	http.StatusNotFound:              apperr.ErrNotFound.Code,
+	http.StatusMethodNotAllowed:      "method_not_allowed",
+	http.StatusRequestEntityTooLarge: "request_too_large",
+	http.StatusUnsupportedMediaType:  "unsupported_media_type",
+	http.StatusTooManyRequests:       "too_many_requests",
+	http.StatusServiceUnavailable:    "service_unavailable",
+}
+
+// NewErrorHandler 创建统一的错误处理器，作为 Echo 的 HTTPErrorHandler.
+//
+// 应用错误按类别映射为HTTP状态码；Echo 的HTTP错误按状态码映射为错误码；其他错误一律视为内部错误，
+// 不向客户端暴露细节. 原始错误由请求日志记录.
+func NewErrorHandler(messages *i18n.Catalog, logger *slog.Logger) echo.HTTPErrorHandler {
+	return func(err error, c echo.Context) {
+		if c.Response().Committed {
+			return
+		}
+
+		status, appErr := classify(err)
+
+		var writeErr error
+		if c.Request().Method == http.MethodHead {
+			writeErr = c.NoContent(status)
+		} else {
+			writeErr = c.JSON(status, errorResponse(c.Request().Context(), messages, appErr))
+		}
+
+		if writeErr != nil {
+			logger.ErrorContext(c.Request().Context(), "写入错误响应失败", "error", writeErr)
+		}
+	}
+}
+
+// classify 确定错误对应的HTTP状态码和应用错误.
+func classify(err error) (int, *apperr.Error) {
+	if appErr, ok := apperr.As(err); ok {
+		return kindStatus[appErr.Kind], appErr
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		code, ok := httpStatusCodes[httpErr.Code]
+
+		switch {
+		case ok:
+		case httpErr.Code >= http.StatusInternalServerError:
+			code = apperr.ErrInternal.Code
+		default:
+			code = apperr.ErrBadRequest.Code
+		}
+
+		return httpErr.Code, &apperr.Error{Code: code}
+	}
+
+	return http.StatusInternalServerError, apperr.ErrInternal
+}
+
+// errorResponse 生成本地化的失败响应. 参数校验错误的消息使用第一个字段的错误消息.
+func errorResponse(ctx context.Context, messages *i18n.Catalog, appErr *apperr.Error) APIResponse {
+	detail := &ErrorDetail{Code: appErr.Code}
+	message := messages.T(ctx, "error."+appErr.Code, nil)
+
+	for i, fe := range appErr.Fields {
+		params := map[string]string{
+			"field": fieldName(ctx, messages, fe.Field),
+			"param": fieldName(ctx, messages, fe.Param),
+		}
+
+		// 没有专门消息的校验规则使用通用消息
+		key := "validation." + fe.Code
+		if _, ok := messages.Lookup(i18n.DefaultLocale, key); !ok {
+			key = "validation.invalid"
+		}
+
+		field := FieldDetail{
+			Field:   fe.Field,
+			Code:    fe.Code,
+			Message: messages.T(ctx, key, params),
+		}
+
+		if i == 0 {
+			message = field.Message
+		}
+
+		detail.Fields = append(detail.Fields, field)
+	}
+
+	return APIResponse{
+		Code:    1,
+		Data:    nil,
+		Message: message,
+		Error:   detail,
+	}
+}
+
+// fieldName 返回字段的本地化名称，消息目录中没有该字段时原样返回. 也用于规则参数，如 nefield 引用的字段.
+func fieldName(ctx context.Context, messages *i18n.Catalog, name string) string {
+	if name == "" {
+		return ""
+	}
+
+	if msg := messages.T(ctx, "field."+name, nil); msg != "field."+name {
+		return msg
+	}
+
+	return name
+}
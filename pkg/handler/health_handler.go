@@ -0,0 +1,97 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-react-template/pkg/health"
+	"go-react-template/pkg/i18n"
+	"go-react-template/pkg/version"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HealthHandler 健康检查HTTP处理器.
+type HealthHandler struct {
+	checks   *health.Registry
+	messages *i18n.Catalog
+}
+
+// NewHealthHandler 创建健康检查处理器实例.
+func NewHealthHandler(checks *health.Registry, messages *i18n.Catalog) *HealthHandler {
+	return &HealthHandler{checks: checks, messages: messages}
+}
+
+// GET /livez 进程存活即返回 200，不检查外部依赖.
+func (h *HealthHandler) Livez(c echo.Context) error {
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"status":  health.StatusOK,
+		"version": version.Version,
+		"commit":  version.Commit,
+	})
+}
+
+// GET /readyz 执行所有就绪检查，全部通过返回 200，否则返回 503.
+func (h *HealthHandler) Readyz(c echo.Context) error {
+	report := h.checks.Check(c.Request().Context())
+
+	status := http.StatusOK
+	if report.Status != health.StatusOK {
+		status = http.StatusServiceUnavailable
+	}
+
+	return c.JSON(status, map[string]interface{}{
+		"status":  report.Status,
+		"version": version.Version,
+		"commit":  version.Commit,
+		"checks":  report.Checks,
+	})
+}
+
+// ServiceInfo 服务信息.
+type ServiceInfo struct {
+	Status  string `json:"status"`
+	Service string `json:"service"`
+	Version string `json:"version"`
+	Commit  string `json:"commit"`
+}
+
+// LegacyHealthResponse GET /api/v1/health 的响应，该接口早于统一响应格式，为兼容保留原有结构.
+type LegacyHealthResponse struct {
+	Success bool        `json:"success"`
+	Data    ServiceInfo `json:"data"`
+	Message string      `json:"message"`
+}
+
+// GET /api/v1/health 已弃用，执行与 /readyz 相同的就绪检查，全部通过返回 200，否则返回 503.
+// 响应携带 Deprecation 和指向 /readyz 的 Link 响应头.
+func (h *HealthHandler) Health(c echo.Context) error {
+	ctx := c.Request().Context()
+	report := h.checks.Check(ctx)
+
+	c.Response().Header().Set("Deprecation", "true")
+	c.Response().Header().Set("Link", `</readyz>; rel="successor-version"`)
+
+	if report.Status != health.StatusOK {
+		return c.JSON(http.StatusServiceUnavailable, LegacyHealthResponse{
+			Success: false,
+			Data:    h.serviceInfo("unhealthy"),
+			Message: h.messages.T(ctx, "error.service_unavailable", nil),
+		})
+	}
+
+	return c.JSON(http.StatusOK, LegacyHealthResponse{
+		Success: true,
+		Data:    h.serviceInfo("healthy"),
+		Message: h.messages.T(ctx, "success.health", nil),
+	})
+}
+
+// serviceInfo 返回指定状态的服务信息.
+func (h *HealthHandler) serviceInfo(status string) ServiceInfo {
+	return ServiceInfo{
+		Status:  status,
+		Service: "go-react-template",
+		Version: version.Version,
+		Commit:  version.Commit,
+	}
+}
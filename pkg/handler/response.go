@@ -0,0 +1,39 @@
+package handler
+
+import (
+	"net/http"
+
+	"go-react-template/pkg/i18n"
+
+	"github.com/labstack/echo/v4"
+)
+
+// APIResponse 统一的API响应格式，Code 为 0 表示成功、1 表示失败.
+type APIResponse struct {
+	Code    int          `json:"code"`
+	Data    interface{}  `json:"data"`
+	Message string       `json:"message"`
+	Error   *ErrorDetail `json:"error,omitempty"`
+}
+
+// ErrorDetail 失败响应的错误详情，Code 为稳定的字符串错误码.
+type ErrorDetail struct {
+	Code   string        `json:"code"`
+	Fields []FieldDetail `json:"fields,omitempty"`
+}
+
+// FieldDetail 单个字段的校验错误.
+type FieldDetail struct {
+	Field   string `json:"field"`
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// success 返回成功响应，messageKey 为消息目录中的键.
+func success(c echo.Context, messages *i18n.Catalog, data interface{}, messageKey string) error {
+	return c.JSON(http.StatusOK, APIResponse{
+		Code:    0,
+		Data:    data,
+		Message: messages.T(c.Request().Context(), messageKey, nil),
+	})
+}
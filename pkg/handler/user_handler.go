@@ -2,8 +2,9 @@
 package handler
 
 import (
-	"net/http"
+	"fmt"
 
+	"go-react-template/pkg/i18n"
 	"go-react-template/pkg/middleware"
 	"go-react-template/pkg/model"
 	"go-react-template/pkg/service"
@@ -14,237 +15,226 @@ import (
 // UserHandler 用户HTTP处理器.
 type UserHandler struct {
 	userService service.UserService
+	loginTokens service.LoginTokenService
+	sessions    *middleware.SessionMiddleware
+	messages    *i18n.Catalog
 }
 
-// NewUserHandler 创建用户HTTP处理器实例.
-func NewUserHandler(userService service.UserService) *UserHandler {
+// NewUserHandler 创建用户HTTP处理器实例. 处理器返回的错误由统一的错误处理器转换为响应.
+func NewUserHandler(userService service.UserService, loginTokens service.LoginTokenService, sessions *middleware.SessionMiddleware, messages *i18n.Catalog) *UserHandler {
 	return &UserHandler{
 		userService: userService,
+		loginTokens: loginTokens,
+		sessions:    sessions,
+		messages:    messages,
 	}
 }
 
 // POST /api/v1/auth/register.
 func (h *UserHandler) Register(c echo.Context) error {
 	var req model.UserRegisterRequest
+	// 绑定时按 validate 标签校验请求
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": "请求参数格式错误",
-		})
+		return err
 	}
 
-	user, err := h.userService.Register(&req)
+	user, err := h.userService.Register(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": err.Error(),
-		})
-	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"code":    0,
-		"data":    user,
-		"message": "注册成功",
-	})
+		return err
+	}
+
+	return success(c, h.messages, user, "success.register")
 }
 
 // POST /api/v1/auth/login.
 func (h *UserHandler) Login(c echo.Context) error {
 	var req model.UserLoginRequest
+	// 绑定时按 validate 标签校验请求
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": "请求参数格式错误",
-		})
+		return err
 	}
 
-	loginResponse, err := h.userService.Login(&req)
+	loginResponse, err := h.userService.Login(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	// 创建session
-	sessionMiddleware := middleware.NewSessionMiddleware()
 	user := &model.User{
 		ID:       loginResponse.User.ID,
 		Username: loginResponse.User.Username,
 		Email:    loginResponse.User.Email,
 	}
 
-	if err := sessionMiddleware.CreateSession(c, user); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": "创建session失败",
-		})
+	if err := h.sessions.CreateSession(c, user); err != nil {
+		return fmt.Errorf("创建session失败: %w", err)
+	}
+
+	if req.RememberMe {
+		if err := h.sessions.Remember(c, user.ID); err != nil {
+			return fmt.Errorf("签发持久登录令牌失败: %w", err)
+		}
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"code":    0,
-		"data":    loginResponse,
-		"message": "登录成功",
-	})
+	return success(c, h.messages, loginResponse, "success.login")
 }
 
-// GET /api/v1/user/profile/:id.
+// GET /api/v1/user/profile.
 func (h *UserHandler) GetProfile(c echo.Context) error {
-	id := c.Param("id")
+	// 从session中获取用户ID
+	userID, err := middleware.ExtractUserIDFromSession(c)
+	if err != nil {
+		return err
+	}
 
-	user, err := h.userService.GetUserByID(id)
+	user, err := h.userService.GetUserByID(c.Request().Context(), userID)
 	if err != nil {
-		return c.JSON(http.StatusNotFound, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": err.Error(),
-		})
-	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"code":    0,
-		"data":    user,
-		"message": "获取成功",
-	})
+		return err
+	}
+
+	return success(c, h.messages, user, "success.get_profile")
 }
 
 // POST /api/v1/auth/google.
 func (h *UserHandler) GoogleLogin(c echo.Context) error {
 	var req model.GoogleLoginRequest
+	// 绑定时按 validate 标签校验请求
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": "请求参数格式错误",
-		})
+		return err
 	}
 
-	loginResponse, err := h.userService.GoogleLogin(&req)
+	loginResponse, err := h.userService.GoogleLogin(c.Request().Context(), &req)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": err.Error(),
-		})
+		return err
 	}
 
 	// 创建session
-	sessionMiddleware := middleware.NewSessionMiddleware()
 	user := &model.User{
 		ID:       loginResponse.User.ID,
 		Username: loginResponse.User.Username,
 		Email:    loginResponse.User.Email,
 	}
 
-	if err := sessionMiddleware.CreateSession(c, user); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": "创建session失败",
-		})
+	if err := h.sessions.CreateSession(c, user); err != nil {
+		return fmt.Errorf("创建session失败: %w", err)
+	}
+
+	if req.RememberMe {
+		if err := h.sessions.Remember(c, user.ID); err != nil {
+			return fmt.Errorf("签发持久登录令牌失败: %w", err)
+		}
 	}
 
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"code":    0,
-		"data":    loginResponse,
-		"message": "Google登录成功",
-	})
+	return success(c, h.messages, loginResponse, "success.google_login")
+}
+
+// CSRFTokenResponse CSRF 令牌，修改数据的请求需要通过 X-CSRF-Token 请求头提交.
+type CSRFTokenResponse struct {
+	Token string `json:"token"`
+}
+
+// GET /api/v1/auth/csrf.
+func (h *UserHandler) CSRFToken(c echo.Context) error {
+	token, err := h.sessions.CSRFToken(c)
+	if err != nil {
+		return fmt.Errorf("生成CSRF令牌失败: %w", err)
+	}
+
+	return success(c, h.messages, CSRFTokenResponse{Token: token}, "success.csrf")
 }
 
 // POST /api/v1/auth/logout.
 func (h *UserHandler) Logout(c echo.Context) error {
 	// 销毁session
-	sessionMiddleware := middleware.NewSessionMiddleware()
-	if err := sessionMiddleware.DestroySession(c); err != nil {
-		return c.JSON(http.StatusInternalServerError, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": "注销失败",
-		})
-	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"code":    0,
-		"data":    nil,
-		"message": "注销成功",
-	})
+	if err := h.sessions.DestroySession(c); err != nil {
+		return fmt.Errorf("销毁session失败: %w", err)
+	}
+
+	return success(c, h.messages, nil, "success.logout")
 }
 
 // PUT /api/v1/user/profile.
 func (h *UserHandler) UpdateProfile(c echo.Context) error {
 	var req model.UserUpdateProfileRequest
+	// 绑定时按 validate 标签校验请求
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": "缺少用户ID参数",
-		})
+		return err
 	}
 
 	// 从session中获取用户ID
 	userID, err := middleware.ExtractUserIDFromSession(c)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": "未授权访问",
-		})
+		return err
 	}
 
-	user, err := h.userService.UpdateProfile(userID, &req)
+	user, err := h.userService.UpdateProfile(c.Request().Context(), userID, &req)
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": err.Error(),
-		})
-	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"code":    0,
-		"data":    user,
-		"message": "更新成功",
-	})
+		return err
+	}
+
+	return success(c, h.messages, user, "success.update_profile")
 }
 
 // POST /api/v1/user/change-password.
 func (h *UserHandler) ChangePassword(c echo.Context) error {
 	var req model.UserChangePasswordRequest
+	// 绑定时按 validate 标签校验请求
 	if err := c.Bind(&req); err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": "请求参数格式错误",
-		})
+		return err
+	}
+
+	// 从session中获取用户ID
+	userID, err := middleware.ExtractUserIDFromSession(c)
+	if err != nil {
+		return err
+	}
+
+	err = h.userService.ChangePassword(c.Request().Context(), userID, &req)
+	if err != nil {
+		return err
 	}
 
+	// 修改密码相当于重新认证，更换会话ID并重新计算有效期
+	if err := h.sessions.RotateSession(c); err != nil {
+		return fmt.Errorf("更换session失败: %w", err)
+	}
+
+	return success(c, h.messages, nil, "success.change_password")
+}
+
+// GET /api/v1/user/login-tokens.
+func (h *UserHandler) ListLoginTokens(c echo.Context) error {
 	// 从session中获取用户ID
 	userID, err := middleware.ExtractUserIDFromSession(c)
 	if err != nil {
-		return c.JSON(http.StatusUnauthorized, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": "未授权访问",
-		})
+		return err
 	}
 
-	err = h.userService.ChangePassword(userID, &req)
+	tokens, err := h.loginTokens.List(c.Request().Context(), userID, h.sessions.LoginTokenID(c))
 	if err != nil {
-		return c.JSON(http.StatusBadRequest, map[string]interface{}{
-			"code":    1,
-			"data":    nil,
-			"message": err.Error(),
-		})
-	}
-
-	return c.JSON(http.StatusOK, map[string]interface{}{
-		"code":    0,
-		"data":    nil,
-		"message": "密码修改成功",
-	})
+		return err
+	}
+
+	return success(c, h.messages, tokens, "success.list_login_tokens")
+}
+
+// DELETE /api/v1/user/login-tokens/:id.
+func (h *UserHandler) RevokeLoginToken(c echo.Context) error {
+	// 从session中获取用户ID
+	userID, err := middleware.ExtractUserIDFromSession(c)
+	if err != nil {
+		return err
+	}
+
+	id := c.Param("id")
+	if err := h.loginTokens.Revoke(c.Request().Context(), userID, id); err != nil {
+		return err
+	}
+
+	// 吊销的是当前浏览器的令牌时同时删除Cookie
+	if id == h.sessions.LoginTokenID(c) {
+		h.sessions.Forget(c)
+	}
+
+	return success(c, h.messages, nil, "success.revoke_login_token")
 }
@@ -0,0 +1,156 @@
+// Package health 存活和就绪检查：就绪检查并发执行所有已注册的检查项并记录每项耗时
+package health
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// 检查状态.
+const (
+	StatusOK   = "ok"
+	StatusFail = "fail"
+)
+
+// CheckFunc 检查函数，返回 nil 表示正常. ctx 带有单项检查的超时.
+type CheckFunc func(ctx context.Context) error
+
+// 对外返回的失败原因. /readyz 是公开接口，具体错误只记录在服务端日志中.
+const (
+	errorFailed  = "检查未通过"
+	errorTimeout = "检查超时"
+)
+
+// CheckResult 单项检查结果.
+type CheckResult struct {
+	Name       string  `json:"name"`
+	Status     string  `json:"status"`
+	DurationMS float64 `json:"duration_ms"`
+	Error      string  `json:"error,omitempty"`
+}
+
+// Report 就绪检查报告.
+type Report struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+// namedCheck 已注册的检查项.
+type namedCheck struct {
+	name  string
+	check CheckFunc
+}
+
+// Registry 就绪检查项注册表，可以在运行时由各组件注册自己的检查项.
+type Registry struct {
+	timeout      time.Duration
+	logger       *slog.Logger
+	shuttingDown atomic.Bool
+
+	mu     sync.RWMutex
+	checks []namedCheck
+}
+
+// NewRegistry 创建检查项注册表，timeout 为单项检查的超时时间，检查失败的具体原因输出到 logger.
+func NewRegistry(timeout time.Duration, logger *slog.Logger) *Registry {
+	return &Registry{timeout: timeout, logger: logger}
+}
+
+// Register 注册检查项，名称重复时覆盖原有检查项.
+func (r *Registry) Register(name string, check CheckFunc) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for i := range r.checks {
+		if r.checks[i].name == name {
+			r.checks[i].check = check
+			return
+		}
+	}
+
+	r.checks = append(r.checks, namedCheck{name: name, check: check})
+}
+
+// SetShuttingDown 标记服务正在关闭，之后的就绪检查直接返回失败，不再执行检查项.
+func (r *Registry) SetShuttingDown() {
+	r.shuttingDown.Store(true)
+}
+
+// Check 并发执行所有检查项，任一项失败时报告状态为 fail. 结果按注册顺序排列.
+func (r *Registry) Check(ctx context.Context) Report {
+	if r.shuttingDown.Load() {
+		return Report{
+			Status: StatusFail,
+			Checks: []CheckResult{{Name: "shutdown", Status: StatusFail, Error: "服务正在关闭"}},
+		}
+	}
+
+	r.mu.RLock()
+	checks := make([]namedCheck, len(r.checks))
+	copy(checks, r.checks)
+	r.mu.RUnlock()
+
+	results := make([]CheckResult, len(checks))
+
+	var wg sync.WaitGroup
+
+	for i, c := range checks {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			results[i] = r.run(ctx, c)
+		}()
+	}
+
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: results}
+
+	for _, result := range results {
+		if result.Status != StatusOK {
+			report.Status = StatusFail
+			break
+		}
+	}
+
+	return report
+}
+
+// run 在超时限制内执行单项检查，检查函数 panic 时视为失败. 结果中只包含概括的失败原因，具体错误输出到日志.
+func (r *Registry) run(ctx context.Context, c namedCheck) (result CheckResult) {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	result = CheckResult{Name: c.name, Status: StatusOK}
+
+	defer func() {
+		if p := recover(); p != nil {
+			r.logger.ErrorContext(ctx, "就绪检查项 panic", "check", c.name, "panic", p)
+
+			result.Status = StatusFail
+			result.Error = errorFailed
+		}
+
+		result.DurationMS = float64(time.Since(start).Microseconds()) / 1000
+	}()
+
+	if err := c.check(ctx); err != nil {
+		r.logger.WarnContext(ctx, "就绪检查项未通过", "check", c.name, "error", err)
+
+		result.Status = StatusFail
+		result.Error = errorFailed
+
+		if errors.Is(err, context.DeadlineExceeded) || errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result.Error = errorTimeout
+		}
+	}
+
+	return result
+}
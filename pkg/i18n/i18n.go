@@ -0,0 +1,157 @@
+// Package i18n 消息目录，消息从内嵌的 locales/<语言>.json 加载，消息中的 {name} 会被替换为参数值
+package i18n
+
+import (
+	"context"
+	"embed"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"path"
+	"sort"
+	"strings"
+
+	"go-react-template/pkg/reqctx"
+
+	"golang.org/x/text/language"
+)
+
+// DefaultLocale 默认语言，其他语言缺少的消息回退到该语言.
+const DefaultLocale = "zh-CN"
+
+//go:embed locales/*.json
+var localeFiles embed.FS
+
+// Catalog 消息目录，加载后只读，可以并发使用.
+type Catalog struct {
+	messages map[string]map[string]string // 语言 -> 消息键 -> 消息
+	locales  []string                     // 支持的语言，默认语言排在第一位
+	matcher  language.Matcher
+}
+
+// Load 加载内嵌的所有语言文件.
+func Load() (*Catalog, error) {
+	files, err := fs.Glob(localeFiles, "locales/*.json")
+	if err != nil {
+		return nil, err
+	}
+
+	c := &Catalog{messages: make(map[string]map[string]string, len(files))}
+
+	for _, file := range files {
+		data, err := localeFiles.ReadFile(file)
+		if err != nil {
+			return nil, err
+		}
+
+		var messages map[string]string
+		if err := json.Unmarshal(data, &messages); err != nil {
+			return nil, fmt.Errorf("解析语言文件 %s 失败: %w", file, err)
+		}
+
+		c.messages[strings.TrimSuffix(path.Base(file), ".json")] = messages
+	}
+
+	if _, ok := c.messages[DefaultLocale]; !ok {
+		return nil, fmt.Errorf("缺少默认语言文件 %s.json", DefaultLocale)
+	}
+
+	// 默认语言排在第一位，无法匹配时 Matcher 返回第一个语言
+	c.locales = append(c.locales, DefaultLocale)
+	for locale := range c.messages {
+		if locale != DefaultLocale {
+			c.locales = append(c.locales, locale)
+		}
+	}
+
+	sort.Strings(c.locales[1:])
+
+	tags := make([]language.Tag, len(c.locales))
+	for i, locale := range c.locales {
+		tag, err := language.Parse(locale)
+		if err != nil {
+			return nil, fmt.Errorf("语言文件名 %s 不是合法的语言标签: %w", locale, err)
+		}
+
+		tags[i] = tag
+	}
+
+	c.matcher = language.NewMatcher(tags)
+
+	return c, nil
+}
+
+// Locales 返回支持的语言，默认语言排在第一位.
+func (c *Catalog) Locales() []string {
+	return c.locales
+}
+
+// Negotiate 根据 X-Language 请求头和 Accept-Language 请求头选择语言.
+// X-Language 为单个语言标签，优先于 Accept-Language；两者都无法匹配时返回默认语言.
+func (c *Catalog) Negotiate(xLanguage, acceptLanguage string) string {
+	if tag, err := language.Parse(strings.TrimSpace(xLanguage)); err == nil {
+		if _, index, confidence := c.matcher.Match(tag); confidence != language.No {
+			return c.locales[index]
+		}
+	}
+
+	if tags, _, err := language.ParseAcceptLanguage(acceptLanguage); err == nil && len(tags) > 0 {
+		if _, index, confidence := c.matcher.Match(tags...); confidence != language.No {
+			return c.locales[index]
+		}
+	}
+
+	return DefaultLocale
+}
+
+// Missing 返回各语言相对默认语言缺少的消息键，没有缺失时返回空 map.
+func (c *Catalog) Missing() map[string][]string {
+	missing := make(map[string][]string)
+
+	for _, locale := range c.locales[1:] {
+		for key := range c.messages[DefaultLocale] {
+			if _, ok := c.messages[locale][key]; !ok {
+				missing[locale] = append(missing[locale], key)
+			}
+		}
+
+		sort.Strings(missing[locale])
+	}
+
+	return missing
+}
+
+// Lookup 查找指定语言的消息，缺失时回退到默认语言. 两者都缺失时返回 false.
+func (c *Catalog) Lookup(locale, key string) (string, bool) {
+	if msg, ok := c.messages[locale][key]; ok {
+		return msg, true
+	}
+
+	msg, ok := c.messages[DefaultLocale][key]
+
+	return msg, ok
+}
+
+// Translate 返回指定语言的消息并替换参数，找不到消息时返回消息键本身.
+func (c *Catalog) Translate(locale, key string, params map[string]string) string {
+	msg, ok := c.Lookup(locale, key)
+	if !ok {
+		return key
+	}
+
+	if len(params) == 0 {
+		return msg
+	}
+
+	pairs := make([]string, 0, len(params)*2)
+	for name, value := range params {
+		pairs = append(pairs, "{"+name+"}", value)
+	}
+
+	return strings.NewReplacer(pairs...).Replace(msg)
+}
+
+// T 按 ctx 中协商好的请求语言翻译消息，未协商时使用默认语言.
+func (c *Catalog) T(ctx context.Context, key string, params map[string]string) string {
+	return c.Translate(reqctx.Locale(ctx), key, params)
+}
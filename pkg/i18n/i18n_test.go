@@ -0,0 +1,14 @@
+package i18n
+
+import "testing"
+
+func TestCatalogComplete(t *testing.T) {
+	messages, err := Load()
+	if err != nil {
+		t.Fatalf("消息目录加载失败: %v", err)
+	}
+
+	for locale, keys := range messages.Missing() {
+		t.Errorf("%s 缺少 %d 条翻译: %v", locale, len(keys), keys)
+	}
+}
@@ -0,0 +1,98 @@
+// Package logging 基于 log/slog 的结构化日志，日志自动携带请求ID和用户ID
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+
+	"go-react-template/configs"
+	"go-react-template/pkg/reqctx"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ParseLevel 将配置中的日志级别转换为 slog 级别.
+func ParseLevel(level string) (slog.Level, error) {
+	switch level {
+	case "debug":
+		return slog.LevelDebug, nil
+	case "info":
+		return slog.LevelInfo, nil
+	case "warn":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return 0, fmt.Errorf("无效的日志级别: %s", level)
+	}
+}
+
+// New 根据当前配置创建日志实例，输出到 w. 日志级别支持热加载，输出格式修改后需要重启.
+func New(cfgManager *configs.Manager, w io.Writer) (*slog.Logger, error) {
+	cfg := cfgManager.Current().Log
+
+	level, err := ParseLevel(cfg.Level)
+	if err != nil {
+		return nil, err
+	}
+
+	levelVar := new(slog.LevelVar)
+	levelVar.Set(level)
+
+	opts := &slog.HandlerOptions{Level: levelVar}
+
+	var handler slog.Handler
+	if cfg.Format == "json" {
+		handler = slog.NewJSONHandler(w, opts)
+	} else {
+		handler = slog.NewTextHandler(w, opts)
+	}
+
+	logger := slog.New(&contextHandler{Handler: handler})
+
+	cfgManager.Subscribe(func(_, updated *configs.Config) {
+		level, err := ParseLevel(updated.Log.Level)
+		if err != nil {
+			logger.Error("更新日志级别失败", "error", err)
+			return
+		}
+
+		levelVar.Set(level)
+	})
+
+	return logger, nil
+}
+
+// contextHandler 从 context 中读取请求ID、用户ID和链路追踪ID并附加到每条日志.
+type contextHandler struct {
+	slog.Handler
+}
+
+// Handle 附加请求信息后交给下层处理器.
+func (h *contextHandler) Handle(ctx context.Context, record slog.Record) error {
+	if requestID := reqctx.RequestID(ctx); requestID != "" {
+		record.AddAttrs(slog.String("request_id", requestID))
+	}
+
+	if userID := reqctx.UserID(ctx); userID != "" {
+		record.AddAttrs(slog.String("user_id", userID))
+	}
+
+	if spanCtx := trace.SpanContextFromContext(ctx); spanCtx.IsValid() {
+		record.AddAttrs(slog.String("trace_id", spanCtx.TraceID().String()), slog.String("span_id", spanCtx.SpanID().String()))
+	}
+
+	return h.Handler.Handle(ctx, record)
+}
+
+// WithAttrs 返回附加了字段的处理器，保持请求信息注入.
+func (h *contextHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithAttrs(attrs)}
+}
+
+// WithGroup 返回带分组的处理器，保持请求信息注入.
+func (h *contextHandler) WithGroup(name string) slog.Handler {
+	return &contextHandler{Handler: h.Handler.WithGroup(name)}
+}
@@ -0,0 +1,230 @@
+// Package metrics Prometheus 指标：HTTP请求、数据库连接池、登录注册、活跃会话以及Go运行时指标
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/labstack/echo/v4"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace 应用指标名前缀.
+const namespace = "app"
+
+// 登录结果标签值.
+const (
+	resultSuccess = "success"
+	resultFailure = "failure"
+)
+
+// unmatchedRoute 未匹配到路由的请求使用的路由标签，避免任意路径造成标签基数膨胀.
+const unmatchedRoute = "unmatched"
+
+// Metrics 应用指标集合，每个实例使用独立的注册表.
+type Metrics struct {
+	registry *prometheus.Registry
+
+	httpRequests *prometheus.CounterVec
+	httpDuration *prometheus.HistogramVec
+	logins       *prometheus.CounterVec
+	registration prometheus.Counter
+
+	sessions *sessionTracker
+}
+
+// New 创建指标集合并注册Go运行时和进程指标. sessionLifetime 为会话有效期，用于估算活跃会话数.
+func New(sessionLifetime time.Duration) *Metrics {
+	m := &Metrics{
+		registry: prometheus.NewRegistry(),
+		httpRequests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "http_requests_total",
+			Help:      "HTTP请求总数，按方法、路由模板和状态码统计.",
+		}, []string{"method", "route", "status"}),
+		httpDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "http_request_duration_seconds",
+			Help:      "HTTP请求处理耗时，按方法、路由模板和状态码统计.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "route", "status"}),
+		logins: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "logins_total",
+			Help:      "登录次数，按登录类型和结果统计.",
+		}, []string{"login_type", "result"}),
+		registration: prometheus.NewCounter(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "registrations_total",
+			Help:      "用户注册成功次数.",
+		}),
+		sessions: newSessionTracker(sessionLifetime),
+	}
+
+	m.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		m.httpRequests,
+		m.httpDuration,
+		m.logins,
+		m.registration,
+		prometheus.NewGaugeFunc(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Name:      "active_sessions",
+			Help:      "估算的活跃会话数：有效期内创建且未注销的会话.",
+		}, m.sessions.active),
+	)
+
+	return m
+}
+
+// Handler 返回输出指标的HTTP处理器.
+func (m *Metrics) Handler() http.Handler {
+	return promhttp.HandlerFor(m.registry, promhttp.HandlerOpts{Registry: m.registry})
+}
+
+// RegisterDB 注册数据库连接池指标，name 用于区分主库和只读副本.
+func (m *Metrics) RegisterDB(name string, db *sql.DB) error {
+	return m.registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// RegisterSlowQueries 注册慢查询次数指标，counts 返回各仓储方法的累计慢查询次数.
+func (m *Metrics) RegisterSlowQueries(counts func() map[string]uint64) error {
+	return m.registry.Register(&slowQueryCollector{
+		desc: prometheus.NewDesc(
+			prometheus.BuildFQName(namespace, "db", "slow_queries_total"),
+			"慢查询次数，按发起查询的仓储方法统计.",
+			[]string{"caller"}, nil,
+		),
+		counts: counts,
+	})
+}
+
+// Middleware 记录HTTP请求数和耗时的中间件，需要注册在错误处理中间件之外才能拿到最终状态码.
+func (m *Metrics) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			status := c.Response().Status
+			if !c.Response().Committed {
+				// 错误尚未写入响应时，按错误对应的状态码统计
+				status = http.StatusInternalServerError
+				if httpErr, ok := err.(*echo.HTTPError); ok {
+					status = httpErr.Code
+				}
+			}
+
+			route := c.Path()
+			if route == "" {
+				route = unmatchedRoute
+			}
+
+			labels := prometheus.Labels{
+				"method": c.Request().Method,
+				"route":  route,
+				"status": strconv.Itoa(status),
+			}
+
+			m.httpRequests.With(labels).Inc()
+			m.httpDuration.With(labels).Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// LoginSucceeded 记录一次成功登录.
+func (m *Metrics) LoginSucceeded(loginType string) {
+	m.logins.WithLabelValues(loginType, resultSuccess).Inc()
+}
+
+// LoginFailed 记录一次失败登录.
+func (m *Metrics) LoginFailed(loginType string) {
+	m.logins.WithLabelValues(loginType, resultFailure).Inc()
+}
+
+// UserRegistered 记录一次注册.
+func (m *Metrics) UserRegistered() {
+	m.registration.Inc()
+}
+
+// SessionCreated 记录一个新会话.
+func (m *Metrics) SessionCreated(createdAt time.Time) {
+	m.sessions.add(createdAt, 1)
+}
+
+// SessionDestroyed 记录一个会话被注销，createdAt 为该会话的创建时间.
+func (m *Metrics) SessionDestroyed(createdAt time.Time) {
+	m.sessions.add(createdAt, -1)
+}
+
+// slowQueryCollector 在采集时读取慢查询计数.
+type slowQueryCollector struct {
+	desc   *prometheus.Desc
+	counts func() map[string]uint64
+}
+
+// Describe 实现 prometheus.Collector.
+func (c *slowQueryCollector) Describe(ch chan<- *prometheus.Desc) {
+	ch <- c.desc
+}
+
+// Collect 实现 prometheus.Collector.
+func (c *slowQueryCollector) Collect(ch chan<- prometheus.Metric) {
+	for caller, n := range c.counts() {
+		ch <- prometheus.MustNewConstMetric(c.desc, prometheus.CounterValue, float64(n), caller)
+	}
+}
+
+// sessionTracker 按创建时间所在的分钟对会话计数，超过有效期的桶视为已过期.
+// Cookie 会话不在服务端保存，过期和清除 Cookie 无法感知，因此结果是估算值.
+type sessionTracker struct {
+	lifetime time.Duration
+
+	mu      sync.Mutex
+	buckets map[int64]int
+}
+
+// newSessionTracker 创建会话计数器.
+func newSessionTracker(lifetime time.Duration) *sessionTracker {
+	return &sessionTracker{
+		lifetime: lifetime,
+		buckets:  make(map[int64]int),
+	}
+}
+
+// add 调整 createdAt 所在分钟的会话数.
+func (t *sessionTracker) add(createdAt time.Time, delta int) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.buckets[createdAt.Truncate(time.Minute).Unix()] += delta
+}
+
+// active 返回有效期内的会话数，同时清理过期的桶.
+func (t *sessionTracker) active() float64 {
+	cutoff := time.Now().Add(-t.lifetime).Truncate(time.Minute).Unix()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	total := 0
+
+	for minute, n := range t.buckets {
+		if minute < cutoff {
+			delete(t.buckets, minute)
+			continue
+		}
+
+		total += n
+	}
+
+	return float64(max(total, 0))
+}
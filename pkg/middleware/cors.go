@@ -0,0 +1,74 @@
+package middleware
+
+import (
+	"sync/atomic"
+
+	"go-react-template/configs"
+
+	"github.com/labstack/echo/v4"
+	"github.com/labstack/echo/v4/middleware"
+)
+
+// corsAllowHeaders 跨域请求允许携带的请求头，与前端和中间件实际使用的请求头保持一致.
+var corsAllowHeaders = []string{
+	echo.HeaderOrigin,
+	echo.HeaderContentType,
+	echo.HeaderAccept,
+	echo.HeaderAuthorization,
+	echo.HeaderXRequestID,
+	HeaderXLanguage,
+	HeaderXCSRFToken,
+}
+
+// corsExposeHeaders 允许跨域请求读取的响应头.
+var corsExposeHeaders = []string{
+	echo.HeaderXRequestID,
+	"Content-Language",
+	echo.HeaderRetryAfter,
+	HeaderXCSRFToken,
+	HeaderRateLimitLimit,
+	HeaderRateLimitRemaining,
+	HeaderRateLimitReset,
+	HeaderRateLimitPolicy,
+}
+
+// CORS 跨域中间件，允许的来源、方法和是否携带 Cookie 取自配置并支持热加载.
+// 未配置允许的来源时不处理跨域请求，只允许同源访问.
+func CORS(cfgManager *configs.Manager) echo.MiddlewareFunc {
+	var current atomic.Pointer[echo.MiddlewareFunc]
+
+	current.Store(newCORS(cfgManager.Current().CORS))
+
+	cfgManager.Subscribe(func(_, updated *configs.Config) {
+		current.Store(newCORS(updated.CORS))
+	})
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			cors := *current.Load()
+			if cors == nil {
+				return next(c)
+			}
+
+			return cors(next)(c)
+		}
+	}
+}
+
+// newCORS 根据配置创建 Echo 的跨域中间件，未配置允许的来源时返回 nil.
+func newCORS(cfg configs.CORSConfig) *echo.MiddlewareFunc {
+	var cors echo.MiddlewareFunc
+
+	if len(cfg.AllowOrigins) > 0 {
+		cors = middleware.CORSWithConfig(middleware.CORSConfig{
+			AllowOrigins:     cfg.AllowOrigins,
+			AllowMethods:     cfg.AllowMethods,
+			AllowHeaders:     corsAllowHeaders,
+			ExposeHeaders:    corsExposeHeaders,
+			AllowCredentials: cfg.AllowCredentials,
+			MaxAge:           int(cfg.MaxAge.Seconds()),
+		})
+	}
+
+	return &cors
+}
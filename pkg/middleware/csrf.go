@@ -0,0 +1,127 @@
+package middleware
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"net/http"
+	"strings"
+
+	"go-react-template/pkg/apperr"
+
+	"github.com/gorilla/sessions"
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderXCSRFToken 携带 CSRF 令牌的请求头，会话ID变化时响应也通过该响应头返回新令牌.
+const HeaderXCSRFToken = echo.HeaderXCSRFToken
+
+// csrfMessagePrefix 计算 CSRF 令牌时加在会话ID前的前缀，与 session 密钥的其他用途区分开.
+const csrfMessagePrefix = "csrf:"
+
+// ErrCSRFInvalid 修改数据的请求缺少 CSRF 令牌或令牌与当前会话不匹配.
+var ErrCSRFInvalid = apperr.New(apperr.KindForbidden, "csrf_invalid")
+
+// CSRF 跨站请求伪造防护中间件（与会话绑定的令牌）.
+//
+// GET、HEAD、OPTIONS 请求直接放行；其余请求必须通过 X-CSRF-Token 请求头提交当前会话的令牌，令牌为会话ID以 session 密钥
+// 计算的 HMAC-SHA256，由 GET /api/v1/auth/csrf 下发. 服务端按请求Cookie中的会话重新计算令牌，没有会话、令牌属于其他会话
+// 或会话已更换时都会被拒绝. 其他站点的页面无法读取令牌，也无法在未通过 CORS 预检的情况下设置自定义请求头.
+//
+// 没有携带会话Cookie和持久登录令牌Cookie、使用 Authorization: Bearer 认证的客户端不依赖浏览器自动携带的Cookie，跳过校验；
+// 携带了Cookie的请求即使有 Bearer 请求头也要校验.
+func (s *SessionMiddleware) CSRF() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			switch req.Method {
+			case http.MethodGet, http.MethodHead, http.MethodOptions:
+				return next(c)
+			}
+
+			if isBearer(req.Header.Get(echo.HeaderAuthorization)) && !s.hasSessionCookie(c) {
+				return next(c)
+			}
+
+			sessionID := s.sessionID(c)
+			if sessionID == "" {
+				return ErrCSRFInvalid
+			}
+
+			token := req.Header.Get(HeaderXCSRFToken)
+			if token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.csrfToken(sessionID))) != 1 {
+				return ErrCSRFInvalid
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// CSRFToken 返回当前会话的 CSRF 令牌. 请求没有会话时创建一个只包含会话ID的未登录会话并写入Cookie，
+// 使登录、注册前也能获取令牌. 登录等操作更换会话ID后旧令牌失效，新令牌通过 X-CSRF-Token 响应头返回.
+func (s *SessionMiddleware) CSRFToken(c echo.Context) (string, error) {
+	session, err := s.Store.Get(c.Request(), s.name)
+	if err != nil {
+		// Cookie 无法解码（如密钥已更换）时使用新的会话
+		session = sessions.NewSession(s.Store, s.name)
+		session.IsNew = true
+	}
+
+	if sessionID, ok := session.Values["session_id"].(string); ok && sessionID != "" {
+		return s.csrfToken(sessionID), nil
+	}
+
+	sessionID, err := newSessionID()
+	if err != nil {
+		return "", err
+	}
+
+	session.Values["session_id"] = sessionID
+	session.Options = s.options(c)
+
+	if err := session.Save(c.Request(), c.Response()); err != nil {
+		return "", err
+	}
+
+	return s.csrfToken(sessionID), nil
+}
+
+// sessionID 返回请求会话的ID，没有会话或会话无法解码时返回空字符串.
+func (s *SessionMiddleware) sessionID(c echo.Context) string {
+	session, err := s.Store.Get(c.Request(), s.name)
+	if err != nil {
+		return ""
+	}
+
+	sessionID, _ := session.Values["session_id"].(string) //nolint:errcheck
+
+	return sessionID
+}
+
+// hasSessionCookie 判断请求是否携带了会话Cookie或持久登录令牌Cookie.
+func (s *SessionMiddleware) hasSessionCookie(c echo.Context) bool {
+	for _, name := range []string{s.name, s.rememberName()} {
+		if cookie, err := c.Cookie(name); err == nil && cookie.Value != "" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// csrfToken 计算会话的 CSRF 令牌.
+func (s *SessionMiddleware) csrfToken(sessionID string) string {
+	mac := hmac.New(sha256.New, s.csrfKey)
+	mac.Write([]byte(csrfMessagePrefix + sessionID))
+
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+// isBearer 判断 Authorization 请求头是否为 Bearer 令牌.
+func isBearer(authorization string) bool {
+	scheme, token, ok := strings.Cut(authorization, " ")
+	return ok && strings.EqualFold(scheme, "Bearer") && strings.TrimSpace(token) != ""
+}
@@ -0,0 +1,139 @@
+package middleware
+
+import (
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"go-react-template/configs"
+
+	"github.com/labstack/echo/v4"
+)
+
+// nopSessionRecorder 不记录会话指标.
+type nopSessionRecorder struct{}
+
+func (nopSessionRecorder) SessionCreated(time.Time)   {}
+func (nopSessionRecorder) SessionDestroyed(time.Time) {}
+
+// newTestSessions 创建使用默认配置的session中间件.
+func newTestSessions() *SessionMiddleware {
+	return NewSessionMiddleware(configs.Default().Session, false, nopSessionRecorder{}, nil, nil, slog.New(slog.DiscardHandler))
+}
+
+// issueCSRFToken 模拟 GET /api/v1/auth/csrf，返回令牌和新会话的Cookie.
+func issueCSRFToken(t *testing.T, e *echo.Echo, s *SessionMiddleware) (string, []*http.Cookie) {
+	t.Helper()
+
+	rec := httptest.NewRecorder()
+	c := e.NewContext(httptest.NewRequest(http.MethodGet, "/api/v1/auth/csrf", nil), rec)
+
+	token, err := s.CSRFToken(c)
+	if err != nil {
+		t.Fatalf("获取CSRF令牌失败: %v", err)
+	}
+
+	cookies := rec.Result().Cookies()
+	if len(cookies) == 0 {
+		t.Fatal("没有会话时应写入会话Cookie")
+	}
+
+	return token, cookies
+}
+
+func TestCSRF(t *testing.T) {
+	e := echo.New()
+	s := newTestSessions()
+
+	token, cookies := issueCSRFToken(t, e, s)
+	otherToken, _ := issueCSRFToken(t, e, s)
+
+	tests := []struct {
+		name          string
+		method        string
+		token         string
+		cookies       []*http.Cookie
+		authorization string
+		wantErr       bool
+	}{
+		{name: "安全方法不校验", method: http.MethodGet},
+		{name: "HEAD 不校验", method: http.MethodHead},
+		{name: "OPTIONS 不校验", method: http.MethodOptions},
+		{name: "当前会话的令牌", method: http.MethodPost, token: token, cookies: cookies},
+		{name: "缺少令牌", method: http.MethodPost, cookies: cookies, wantErr: true},
+		{name: "令牌不匹配", method: http.MethodPost, token: token + "x", cookies: cookies, wantErr: true},
+		{name: "其他会话的令牌", method: http.MethodPost, token: otherToken, cookies: cookies, wantErr: true},
+		{name: "没有会话", method: http.MethodPost, token: token, wantErr: true},
+		{name: "没有Cookie的 Bearer 请求", method: http.MethodPost, authorization: "Bearer api-client-token"},
+		{name: "携带会话Cookie的 Bearer 请求", method: http.MethodDelete, cookies: cookies, authorization: "Bearer api-client-token", wantErr: true},
+		{name: "携带记住我Cookie的 Bearer 请求", method: http.MethodPost, cookies: []*http.Cookie{{Name: s.rememberName(), Value: "series.secret"}}, authorization: "Bearer api-client-token", wantErr: true},
+		{name: "空的 Bearer 令牌", method: http.MethodPost, authorization: "Bearer ", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/api/v1/auth/login", nil)
+			for _, cookie := range tt.cookies {
+				req.AddCookie(cookie)
+			}
+
+			if tt.token != "" {
+				req.Header.Set(HeaderXCSRFToken, tt.token)
+			}
+
+			if tt.authorization != "" {
+				req.Header.Set(echo.HeaderAuthorization, tt.authorization)
+			}
+
+			called := false
+			handler := s.CSRF()(func(echo.Context) error {
+				called = true
+				return nil
+			})
+
+			err := handler(e.NewContext(req, httptest.NewRecorder()))
+
+			if tt.wantErr {
+				if !errors.Is(err, ErrCSRFInvalid) || called {
+					t.Fatalf("应拒绝请求，err = %v, called = %v", err, called)
+				}
+
+				return
+			}
+
+			if err != nil || !called {
+				t.Fatalf("应放行请求，err = %v, called = %v", err, called)
+			}
+		})
+	}
+}
+
+func TestCSRFTokenStableWithinSession(t *testing.T) {
+	e := echo.New()
+	s := newTestSessions()
+
+	token, cookies := issueCSRFToken(t, e, s)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/auth/csrf", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	rec := httptest.NewRecorder()
+
+	again, err := s.CSRFToken(e.NewContext(req, rec))
+	if err != nil {
+		t.Fatalf("获取CSRF令牌失败: %v", err)
+	}
+
+	if again != token {
+		t.Fatal("同一会话应返回相同的令牌")
+	}
+
+	if len(rec.Result().Cookies()) != 0 {
+		t.Fatal("已有会话时不应重写Cookie")
+	}
+}
@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"go-react-template/pkg/i18n"
+	"go-react-template/pkg/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// HeaderXLanguage 客户端指定语言的请求头，优先于 Accept-Language.
+const HeaderXLanguage = "X-Language"
+
+// Locale 语言协商中间件：依次根据 X-Language 和 Accept-Language 选择消息目录中支持的语言，
+// 放入请求 context 并写入 Content-Language 响应头.
+func Locale(messages *i18n.Catalog) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			locale := messages.Negotiate(req.Header.Get(HeaderXLanguage), req.Header.Get("Accept-Language"))
+
+			header := c.Response().Header()
+			header.Set("Content-Language", locale)
+			header.Add(echo.HeaderVary, HeaderXLanguage)
+			header.Add(echo.HeaderVary, "Accept-Language")
+
+			c.SetRequest(req.WithContext(reqctx.WithLocale(req.Context(), locale)))
+
+			return next(c)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package middleware
+
+import (
+	"net"
+	"net/http"
+	"strings"
+
+	"go-react-template/pkg/reqctx"
+
+	"github.com/labstack/echo/v4"
+)
+
+// forwardedHeaders 反向代理设置的转发请求头，c.Scheme() 和 c.RealIP() 会读取这些请求头.
+var forwardedHeaders = []string{
+	echo.HeaderXForwardedFor,
+	echo.HeaderXRealIP,
+	echo.HeaderXForwardedProto,
+	echo.HeaderXForwardedProtocol,
+	echo.HeaderXForwardedSsl,
+	echo.HeaderXUrlScheme,
+}
+
+// ParseTrustedProxies 解析可信代理的地址或网段，单个 IP 视为只包含该地址的网段.
+func ParseTrustedProxies(values []string) ([]*net.IPNet, error) {
+	networks := make([]*net.IPNet, 0, len(values))
+
+	for _, value := range values {
+		if !strings.Contains(value, "/") {
+			ip := net.ParseIP(value)
+			if ip == nil {
+				return nil, &net.ParseError{Type: "IP address", Text: value}
+			}
+
+			bits := 8 * net.IPv6len
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 8*net.IPv4len
+			}
+
+			networks = append(networks, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+
+			continue
+		}
+
+		_, network, err := net.ParseCIDR(value)
+		if err != nil {
+			return nil, err
+		}
+
+		networks = append(networks, network)
+	}
+
+	return networks, nil
+}
+
+// TrustedProxies 只采信可信反向代理设置的转发请求头：直连地址不在 trusted 中时删除 X-Forwarded-* 等请求头，
+// 使客户端无法通过伪造请求头冒充 HTTPS 请求或其他来源 IP. 解析出的客户端IP放入请求 context. 需要作为第一个中间件注册.
+func TrustedProxies(trusted []*net.IPNet) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			if !isTrustedPeer(req, trusted) {
+				for _, name := range forwardedHeaders {
+					req.Header.Del(name)
+				}
+			}
+
+			c.SetRequest(req.WithContext(reqctx.WithClientIP(req.Context(), c.RealIP())))
+
+			return next(c)
+		}
+	}
+}
+
+// IPExtractor 返回从 X-Forwarded-For 中提取客户端 IP 的方法，跳过可信代理的地址.
+func IPExtractor(trusted []*net.IPNet) echo.IPExtractor {
+	options := []echo.TrustOption{
+		echo.TrustLoopback(false),
+		echo.TrustLinkLocal(false),
+		echo.TrustPrivateNet(false),
+	}
+
+	for _, network := range trusted {
+		options = append(options, echo.TrustIPRange(network))
+	}
+
+	return echo.ExtractIPFromXFFHeader(options...)
+}
+
+// isTrustedPeer 判断直连地址是否为可信代理.
+func isTrustedPeer(req *http.Request, trusted []*net.IPNet) bool {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		host = req.RemoteAddr
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil {
+		return false
+	}
+
+	for _, network := range trusted {
+		if network.Contains(ip) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,183 @@
+package middleware
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"log/slog"
+	"strconv"
+	"sync/atomic"
+	"time"
+
+	"go-react-template/configs"
+	"go-react-template/pkg/ratelimit"
+
+	"github.com/labstack/echo/v4"
+)
+
+// 限流相关的响应头，参考 IETF draft-ietf-httpapi-ratelimit-headers.
+const (
+	HeaderRateLimitLimit     = "RateLimit-Limit"
+	HeaderRateLimitRemaining = "RateLimit-Remaining"
+	HeaderRateLimitReset     = "RateLimit-Reset"
+	HeaderRateLimitPolicy    = "RateLimit-Policy"
+)
+
+// 限流键.
+const (
+	rateLimitKeyIP    = "ip"
+	rateLimitKeyUser  = "user"
+	rateLimitKeyToken = "token"
+)
+
+// RequestIdentity 识别请求的登录用户和登录凭证，由 SessionMiddleware 实现.
+//
+// 两个方法都只返回服务端已经校验过的值，未登录时返回空字符串；限流中间件可能注册在认证中间件之前，
+// 因此不能只依赖认证中间件写入context的用户信息.
+type RequestIdentity interface {
+	UserID(c echo.Context) string
+	SessionID(c echo.Context) string
+}
+
+// RateLimiter 按路由组限流的令牌桶中间件，策略、管理员名单和开关取自配置并支持热加载.
+type RateLimiter struct {
+	store    ratelimit.Store
+	identity RequestIdentity
+	logger   *slog.Logger
+	state    atomic.Pointer[rateLimitState]
+}
+
+// rateLimitState 从配置解析出的限流状态.
+type rateLimitState struct {
+	enabled  bool
+	admins   map[string]struct{}
+	policies map[string]rateLimitPolicy
+}
+
+// rateLimitPolicy 解析后的路由组限流策略.
+type rateLimitPolicy struct {
+	limit ratelimit.Limit
+	key   string
+}
+
+// NewRateLimiter 创建限流中间件. identity 用于按用户或登录凭证限流以及识别管理员，限流存储的错误输出到 logger.
+func NewRateLimiter(cfgManager *configs.Manager, store ratelimit.Store, identity RequestIdentity, logger *slog.Logger) *RateLimiter {
+	r := &RateLimiter{
+		store:    store,
+		identity: identity,
+		logger:   logger,
+	}
+
+	r.state.Store(newRateLimitState(cfgManager.Current().RateLimit))
+
+	cfgManager.Subscribe(func(_, updated *configs.Config) {
+		r.state.Store(newRateLimitState(updated.RateLimit))
+	})
+
+	return r
+}
+
+// newRateLimitState 解析限流配置. 配置加载时已校验速率格式，这里解析失败的策略按不限流处理.
+func newRateLimitState(cfg configs.RateLimitConfig) *rateLimitState {
+	state := &rateLimitState{
+		enabled:  cfg.Enabled,
+		admins:   make(map[string]struct{}, len(cfg.AdminUsers)),
+		policies: make(map[string]rateLimitPolicy),
+	}
+
+	for _, id := range cfg.AdminUsers {
+		state.admins[id] = struct{}{}
+	}
+
+	for group, policy := range cfg.Policies() {
+		limit, err := ratelimit.ParseLimit(policy.Limit)
+		if err != nil || limit.IsZero() {
+			continue
+		}
+
+		state.policies[group] = rateLimitPolicy{limit: limit, key: policy.Key}
+	}
+
+	return state
+}
+
+// Limit 返回 group 路由组的限流中间件. 未开启限流、该组未配置速率或当前用户为管理员时直接放行.
+//
+// 放行的请求返回 RateLimit-* 响应头，多个路由组的限流叠加时保留剩余次数最少的一组；超出限制时返回 429 和 Retry-After.
+// 限流存储出错时放行请求，避免存储故障导致整个服务不可用.
+func (r *RateLimiter) Limit(group string) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			state := r.state.Load()
+			if !state.enabled {
+				return next(c)
+			}
+
+			policy, ok := state.policies[group]
+			if !ok {
+				return next(c)
+			}
+
+			userID := r.identity.UserID(c)
+			if _, ok := state.admins[userID]; ok && userID != "" {
+				return next(c)
+			}
+
+			key := group + ":" + r.key(c, policy.key, userID)
+
+			result, err := r.store.Take(c.Request().Context(), key, policy.limit)
+			if err != nil {
+				r.logger.ErrorContext(c.Request().Context(), "限流存储出错，放行请求", "group", group, "error", err)
+				return next(c)
+			}
+
+			setRateLimitHeaders(c, policy.limit, result)
+
+			if !result.Allowed {
+				c.Response().Header().Set(echo.HeaderRetryAfter, strconv.Itoa(max(ceilSeconds(result.RetryAfter), 1)))
+				return echo.ErrTooManyRequests
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// key 返回请求的限流键，user 和 token 未登录时退回客户端IP.
+//
+// token 按服务端校验过的登录凭证（签名有效、未超时且未注销的会话）限流，键中只保存会话ID的哈希.
+// 不使用请求携带但服务端没有校验的凭证（如 Authorization: Bearer 令牌）：随意构造的值都会得到新的令牌桶，
+// 客户端可以借此绕过限流.
+func (r *RateLimiter) key(c echo.Context, kind, userID string) string {
+	switch kind {
+	case rateLimitKeyUser:
+		if userID != "" {
+			return rateLimitKeyUser + ":" + userID
+		}
+	case rateLimitKeyToken:
+		if sessionID := r.identity.SessionID(c); sessionID != "" {
+			sum := sha256.Sum256([]byte(sessionID))
+			return rateLimitKeyToken + ":" + hex.EncodeToString(sum[:])
+		}
+	}
+
+	return rateLimitKeyIP + ":" + c.RealIP()
+}
+
+// setRateLimitHeaders 写入限流响应头. 外层路由组已写入且剩余次数更少时保留外层的响应头.
+func setRateLimitHeaders(c echo.Context, limit ratelimit.Limit, result ratelimit.Result) {
+	header := c.Response().Header()
+
+	if existing, err := strconv.Atoi(header.Get(HeaderRateLimitRemaining)); err == nil && existing < result.Remaining {
+		return
+	}
+
+	header.Set(HeaderRateLimitLimit, strconv.Itoa(limit.Requests))
+	header.Set(HeaderRateLimitRemaining, strconv.Itoa(result.Remaining))
+	header.Set(HeaderRateLimitReset, strconv.Itoa(ceilSeconds(result.Reset)))
+	header.Set(HeaderRateLimitPolicy, strconv.Itoa(limit.Requests)+";w="+strconv.Itoa(ceilSeconds(limit.Period)))
+}
+
+// ceilSeconds 将时长向上取整为秒.
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
@@ -0,0 +1,171 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-react-template/configs"
+	"go-react-template/pkg/ratelimit"
+
+	"github.com/labstack/echo/v4"
+)
+
+// fakeIdentity 返回固定的登录用户和会话.
+type fakeIdentity struct {
+	userID    string
+	sessionID string
+}
+
+func (f fakeIdentity) UserID(echo.Context) string    { return f.userID }
+func (f fakeIdentity) SessionID(echo.Context) string { return f.sessionID }
+
+// stubStore 记录限流键并返回固定的结果.
+type stubStore struct {
+	result ratelimit.Result
+	err    error
+	keys   []string
+}
+
+func (s *stubStore) Take(_ context.Context, key string, _ ratelimit.Limit) (ratelimit.Result, error) {
+	s.keys = append(s.keys, key)
+	return s.result, s.err
+}
+
+// newTestRateLimiter 创建只对 global 组按 key 限流 2/1m 的限流中间件.
+func newTestRateLimiter(store ratelimit.Store, identity RequestIdentity, key string, admins ...string) *RateLimiter {
+	cfg := configs.Default()
+	cfg.RateLimit.Enabled = true
+	cfg.RateLimit.AdminUsers = admins
+	cfg.RateLimit.GlobalLimit = "2/1m"
+	cfg.RateLimit.GlobalKey = key
+
+	return NewRateLimiter(configs.NewStaticManager(cfg), store, identity, slog.New(slog.DiscardHandler))
+}
+
+// serveLimited 经过 global 组的限流中间件处理一次请求，返回响应、是否调用了后续处理器和错误.
+func serveLimited(r *RateLimiter) (*httptest.ResponseRecorder, bool, error) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/user/profile", nil)
+	req.RemoteAddr = "192.0.2.1:1234"
+
+	rec := httptest.NewRecorder()
+	called := false
+
+	err := r.Limit(configs.RateLimitGlobal)(func(echo.Context) error {
+		called = true
+		return nil
+	})(echo.New().NewContext(req, rec))
+
+	return rec, called, err
+}
+
+func TestRateLimitHeaders(t *testing.T) {
+	r := newTestRateLimiter(ratelimit.NewMemoryStore(), fakeIdentity{}, "ip")
+
+	rec, called, err := serveLimited(r)
+	if err != nil || !called {
+		t.Fatalf("第一次请求应放行，err = %v, called = %v", err, called)
+	}
+
+	want := map[string]string{
+		HeaderRateLimitLimit:     "2",
+		HeaderRateLimitRemaining: "1",
+		HeaderRateLimitReset:     "30",
+		HeaderRateLimitPolicy:    "2;w=60",
+	}
+	for name, value := range want {
+		if got := rec.Header().Get(name); got != value {
+			t.Errorf("%s = %q, want %q", name, got, value)
+		}
+	}
+
+	if rec.Header().Get(echo.HeaderRetryAfter) != "" {
+		t.Error("放行的请求不应带 Retry-After")
+	}
+}
+
+func TestRateLimitExceeded(t *testing.T) {
+	r := newTestRateLimiter(ratelimit.NewMemoryStore(), fakeIdentity{}, "ip")
+
+	for range 2 {
+		if _, _, err := serveLimited(r); err != nil {
+			t.Fatalf("配额内的请求应放行: %v", err)
+		}
+	}
+
+	rec, called, err := serveLimited(r)
+	if !errors.Is(err, echo.ErrTooManyRequests) || called {
+		t.Fatalf("超出配额应返回 429，err = %v, called = %v", err, called)
+	}
+
+	if got := rec.Header().Get(echo.HeaderRetryAfter); got != "30" {
+		t.Errorf("Retry-After = %q, want %q", got, "30")
+	}
+
+	if got := rec.Header().Get(HeaderRateLimitRemaining); got != "0" {
+		t.Errorf("%s = %q, want %q", HeaderRateLimitRemaining, got, "0")
+	}
+}
+
+func TestRateLimitStoreErrorFailsOpen(t *testing.T) {
+	r := newTestRateLimiter(&stubStore{err: errors.New("存储不可用")}, fakeIdentity{}, "ip")
+
+	rec, called, err := serveLimited(r)
+	if err != nil || !called {
+		t.Fatalf("限流存储出错时应放行，err = %v, called = %v", err, called)
+	}
+
+	if rec.Header().Get(HeaderRateLimitLimit) != "" {
+		t.Error("限流存储出错时不应写入限流响应头")
+	}
+}
+
+func TestRateLimitAdminExempt(t *testing.T) {
+	store := &stubStore{}
+	r := newTestRateLimiter(store, fakeIdentity{userID: "admin-1"}, "user", "admin-1")
+
+	if _, called, err := serveLimited(r); err != nil || !called {
+		t.Fatalf("管理员应直接放行，err = %v, called = %v", err, called)
+	}
+
+	if len(store.keys) != 0 {
+		t.Fatal("管理员的请求不应计数")
+	}
+}
+
+func TestRateLimitKey(t *testing.T) {
+	tests := []struct {
+		name     string
+		key      string
+		identity fakeIdentity
+		want     string
+	}{
+		{name: "按IP", key: "ip", identity: fakeIdentity{userID: "user-1", sessionID: "session-1"}, want: "global:ip:192.0.2.1"},
+		{name: "按用户", key: "user", identity: fakeIdentity{userID: "user-1", sessionID: "session-1"}, want: "global:user:user-1"},
+		{name: "未登录时按用户退回IP", key: "user", want: "global:ip:192.0.2.1"},
+		{name: "按登录凭证", key: "token", identity: fakeIdentity{userID: "user-1", sessionID: "session-1"}, want: "global:token:"},
+		{name: "没有登录凭证时退回IP", key: "token", want: "global:ip:192.0.2.1"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			store := &stubStore{result: ratelimit.Result{Allowed: true}}
+
+			if _, _, err := serveLimited(newTestRateLimiter(store, tt.identity, tt.key)); err != nil {
+				t.Fatalf("请求应放行: %v", err)
+			}
+
+			if len(store.keys) != 1 || !strings.HasPrefix(store.keys[0], tt.want) {
+				t.Fatalf("限流键 = %v, want %s", store.keys, tt.want)
+			}
+
+			if strings.Contains(store.keys[0], "session-1") {
+				t.Fatal("限流键不应包含会话ID原文")
+			}
+		})
+	}
+}
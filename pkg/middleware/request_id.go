@@ -0,0 +1,46 @@
+package middleware
+
+import (
+	"go-react-template/pkg/reqctx"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// maxRequestIDLength 客户端传入的请求ID最大长度，超出时重新生成.
+const maxRequestIDLength = 128
+
+// RequestID 请求ID中间件：沿用客户端或网关传入的 X-Request-ID，没有或不合法时生成新的ID，
+// 写入响应头并放入请求 context，供日志关联使用.
+func RequestID() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+
+			requestID := req.Header.Get(echo.HeaderXRequestID)
+			if !validRequestID(requestID) {
+				requestID = uuid.NewString()
+			}
+
+			c.Response().Header().Set(echo.HeaderXRequestID, requestID)
+			c.SetRequest(req.WithContext(reqctx.WithRequestID(req.Context(), requestID)))
+
+			return next(c)
+		}
+	}
+}
+
+// validRequestID 只接受长度受限的可打印ASCII字符，避免日志注入.
+func validRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+
+	return true
+}
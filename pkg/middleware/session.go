@@ -1,113 +1,302 @@
 package middleware
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"strings"
 	"time"
 
 	"go-react-template/configs"
+	"go-react-template/pkg/apperr"
 	"go-react-template/pkg/model"
+	"go-react-template/pkg/reqctx"
 
+	"github.com/gorilla/securecookie"
 	"github.com/gorilla/sessions"
 	"github.com/labstack/echo/v4"
 )
 
+// sessionIDSize 会话ID的随机字节数.
+const sessionIDSize = 16
+
+// lastSeenInterval 记录最后活动时间的最小间隔，避免每个请求都重写Cookie.
+const lastSeenInterval = time.Minute
+
+// SessionRecorder 会话指标记录器，用于统计活跃会话数.
+type SessionRecorder interface {
+	SessionCreated(createdAt time.Time)
+	SessionDestroyed(createdAt time.Time)
+}
+
+// rememberCookieSuffix "记住我"Cookie名称的后缀，完整名称为会话Cookie名称加该后缀.
+const rememberCookieSuffix = "-remember"
+
+// LoginTokens "记住我"持久登录令牌的签发、校验和吊销，由 service.LoginTokenService 实现.
+type LoginTokens interface {
+	Issue(ctx context.Context, userID string, client model.LoginClient) (string, error)
+	Authenticate(ctx context.Context, token string, client model.LoginClient) (*model.User, string, error)
+	Exists(ctx context.Context, id string) (bool, error)
+	Revoke(ctx context.Context, userID, id string) error
+}
+
+// SessionRevocations 已注销会话的记录和查询，由 service.SessionRevocationService 实现.
+//
+// 会话数据保存在浏览器的Cookie中，注销或更换会话ID后旧Cookie在到期前仍能通过签名校验，需要由服务端记录并拒绝.
+type SessionRevocations interface {
+	Revoke(ctx context.Context, sessionID, userID string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+// revokedKeyPrefix 在请求context中缓存会话注销查询结果的键前缀，完整的键为前缀加会话ID.
+const revokedKeyPrefix = "session_revoked:"
+
 // SessionMiddleware session中间件配置.
 type SessionMiddleware struct {
-	Store *sessions.CookieStore
+	Store    *sessions.CookieStore
+	name     string
+	csrfKey  []byte        // 计算 CSRF 令牌的密钥，即 session 密钥
+	secure   bool          // 总是设置 Secure，生产模式或 SameSite=None 时开启
+	absolute time.Duration // 登录后的有效期，开启滑动续期时每次续期重新计算
+	idle     time.Duration // 无活动超时，0 表示不限制
+	recorder SessionRecorder
+
+	refreshAfter float64       // 有效期过去该比例后续期，0 表示不续期
+	maxLifetime  time.Duration // 续期的上限，从登录时间开始计算
+
+	tokens      LoginTokens   // 为 nil 时不支持"记住我"
+	rememberTTL time.Duration // 持久登录令牌Cookie的有效期
+
+	revocations SessionRevocations // 为 nil 时不记录也不检查已注销的会话
+
+	logger *slog.Logger
 }
 
-// NewSessionMiddleware 创建session中间件实例.
-func NewSessionMiddleware() *SessionMiddleware {
+// NewSessionMiddleware 创建session中间件实例，会话的创建和注销会通知 recorder.
+// tokens 为 nil 或未配置 session.remember_me_ttl 时不支持"记住我"；注销和更换掉的会话ID记录到 revocations，
+// 认证时拒绝，revocations 为 nil 时这些会话的Cookie在到期前仍然有效. 会话处理中的错误输出到 logger.
+//
+// Cookie 的 Secure 属性在配置开启、生产模式、SameSite=None 或请求为 HTTPS（含可信代理转发的
+// X-Forwarded-Proto: https）时设置.
+func NewSessionMiddleware(cfg configs.SessionConfig, production bool, recorder SessionRecorder, tokens LoginTokens, revocations SessionRevocations, logger *slog.Logger) *SessionMiddleware {
 	// 使用Session secret作为session的密钥
-	store := sessions.NewCookieStore([]byte(configs.AppConfig.Session.Secret))
+	store := sessions.NewCookieStore([]byte(cfg.Secret))
+
+	sameSite := parseSameSite(cfg.CookieSameSite)
 
 	// 配置session选项
 	store.Options = &sessions.Options{
 		Path:     "/",
-		MaxAge:   configs.AppConfig.Session.ExpireHour * 3600, // 转换为秒
+		Domain:   cfg.CookieDomain,
+		MaxAge:   int(cfg.CookieMaxAge.Seconds()), // 0 表示浏览器关闭后失效
 		HttpOnly: true,
-		Secure:   false, // 在开发环境中设为false，生产环境应设为true
-		SameSite: http.SameSiteLaxMode,
+		SameSite: sameSite,
+	}
+
+	if cfg.RememberMeTTL <= 0 {
+		tokens = nil
 	}
 
 	return &SessionMiddleware{
-		Store: store,
+		Store:    store,
+		name:     cfg.CookieName,
+		csrfKey:  []byte(cfg.Secret),
+		secure:   cfg.CookieSecure || production || sameSite == http.SameSiteNoneMode,
+		absolute: time.Duration(cfg.ExpireHour) * time.Hour,
+		idle:     cfg.IdleTimeout,
+		recorder: recorder,
+
+		refreshAfter: cfg.RefreshAfter,
+		maxLifetime:  cfg.MaxLifetime,
+
+		tokens:      tokens,
+		rememberTTL: cfg.RememberMeTTL,
+
+		revocations: revocations,
+
+		logger: logger,
 	}
 }
 
-// Session 创建session认证中间件函数.
-func Session() echo.MiddlewareFunc {
-	sessionMiddleware := NewSessionMiddleware()
-	return sessionMiddleware.SessionAuth()
+// parseSameSite 将配置中的 SameSite 转换为 http.SameSite，默认 Lax.
+func parseSameSite(value string) http.SameSite {
+	switch strings.ToLower(value) {
+	case "strict":
+		return http.SameSiteStrictMode
+	case "none":
+		return http.SameSiteNoneMode
+	default:
+		return http.SameSiteLaxMode
+	}
 }
 
-// OptionalSession 创建可选session认证中间件函数.
-func OptionalSession() echo.MiddlewareFunc {
-	sessionMiddleware := NewSessionMiddleware()
-	return sessionMiddleware.OptionalSessionAuth()
+// CreateSession 为登录用户创建新的session.
+//
+// 不沿用请求中已有的session：每次登录都生成新的会话ID和数据，防止会话固定攻击.
+func (s *SessionMiddleware) CreateSession(c echo.Context, user *model.User) error {
+	return s.startSession(c, user.ID, user.Username, user.Email, "")
 }
 
-// CreateSession 创建用户session.
-func (s *SessionMiddleware) CreateSession(c echo.Context, user *model.User) error {
-	session, err := s.Store.Get(c.Request(), "user-session")
+// RotateSession 为当前登录用户更换会话ID并重新开始计算有效期，用于修改密码等权限变化之后.
+func (s *SessionMiddleware) RotateSession(c echo.Context) error {
+	session, err := s.Store.Get(c.Request(), s.name)
 	if err != nil {
 		return err
 	}
 
+	userID, ok := session.Values["user_id"].(string)
+	if !ok || userID == "" {
+		return apperr.ErrUnauthenticated
+	}
+
+	username, _ := session.Values["username"].(string) //nolint:errcheck
+	email, _ := session.Values["email"].(string)       //nolint:errcheck
+
+	if err := s.startSession(c, userID, username, email, ""); err != nil {
+		return err
+	}
+
+	// 记住登录的浏览器同时换发新的持久登录令牌，旧令牌由调用方吊销
+	if s.LoginTokenID(c) != "" {
+		return s.Remember(c, userID)
+	}
+
+	return nil
+}
+
+// startSession 丢弃请求中已有的session，生成新的会话ID和数据并写入Cookie.
+// series 为恢复登录所用持久登录令牌的系列ID，该系列被吊销后会话随之失效；不是恢复登录时为空.
+func (s *SessionMiddleware) startSession(c echo.Context, userID, username, email, series string) error {
+	// 已登录时重新登录，旧会话计为注销，旧Cookie不能再使用
+	if old, err := s.Store.Get(c.Request(), s.name); err == nil {
+		if createdAt, ok := old.Values["created_at"].(int64); ok {
+			s.revoke(c, old)
+			s.recorder.SessionDestroyed(time.Unix(createdAt, 0))
+		}
+	}
+
+	sessionID, err := newSessionID()
+	if err != nil {
+		return err
+	}
+
+	session := sessions.NewSession(s.Store, s.name)
+	session.IsNew = true
+	session.Options = s.options(c)
+
 	// 设置session数据
-	session.Values["user_id"] = user.ID
-	session.Values["username"] = user.Username
-	session.Values["email"] = user.Email
+	now := time.Now()
+	session.Values["session_id"] = sessionID
+	session.Values["user_id"] = userID
+	session.Values["username"] = username
+	session.Values["email"] = email
 	session.Values["authenticated"] = true
-	session.Values["created_at"] = time.Now().Unix()
+	session.Values["created_at"] = now.Unix()
+	session.Values["last_seen_at"] = now.Unix()
+	session.Values["expires_at"] = now.Add(s.absolute).Unix()
+
+	if series != "" {
+		session.Values["login_series"] = series
+	}
 
 	// 保存session
-	return session.Save(c.Request(), c.Response())
+	if err := session.Save(c.Request(), c.Response()); err != nil {
+		return err
+	}
+
+	// 会话ID变化后旧的 CSRF 令牌失效，前端从响应头取得新令牌
+	c.Response().Header().Set(HeaderXCSRFToken, s.csrfToken(sessionID))
+	c.Set("session_id", sessionID)
+
+	s.recorder.SessionCreated(now)
+
+	return nil
 }
 
 // DestroySession 销毁用户session.
 func (s *SessionMiddleware) DestroySession(c echo.Context) error {
-	session, err := s.Store.Get(c.Request(), "user-session")
+	session, err := s.Store.Get(c.Request(), s.name)
 	if err != nil {
 		return err
 	}
 
-	// 清空session数据
+	createdAt, hasCreatedAt := session.Values["created_at"].(int64)
+	userID, _ := session.Values["user_id"].(string) //nolint:errcheck
+
+	// 注销时吊销当前浏览器的持久登录令牌并删除Cookie，否则下次访问会自动恢复登录
+	if id := s.LoginTokenID(c); id != "" {
+		if s.tokens != nil && userID != "" {
+			if err := s.tokens.Revoke(c.Request().Context(), userID, id); err != nil && !isNotFound(err) {
+				// 浏览器中的令牌随后被删除，吊销失败不影响注销
+				s.logger.ErrorContext(c.Request().Context(), "吊销持久登录令牌失败", "error", err)
+			}
+		}
+
+		s.Forget(c)
+	}
+
+	s.revoke(c, session)
+
+	if err := s.expire(c, session); err != nil {
+		return err
+	}
+
+	if hasCreatedAt {
+		s.recorder.SessionDestroyed(time.Unix(createdAt, 0))
+	}
+
+	return nil
+}
+
+// expire 清空session并让浏览器删除Cookie.
+func (s *SessionMiddleware) expire(c echo.Context, session *sessions.Session) error {
 	session.Values = make(map[interface{}]interface{})
+	session.Options = s.options(c)
 	session.Options.MaxAge = -1 // 立即过期
 
-	// 保存session（实际上是删除）
 	return session.Save(c.Request(), c.Response())
 }
 
-// SessionAuth session认证中间件.
-func (s *SessionMiddleware) SessionAuth() echo.MiddlewareFunc {
-	return func(next echo.HandlerFunc) echo.HandlerFunc {
-		return func(c echo.Context) error {
-			session, err := s.Store.Get(c.Request(), "user-session")
-			if err != nil {
-				return echo.NewHTTPError(http.StatusUnauthorized, "无效的session")
-			}
+// options 返回本次请求写入Cookie使用的选项.
+func (s *SessionMiddleware) options(c echo.Context) *sessions.Options {
+	opts := *s.Store.Options
+	opts.Secure = s.secure || c.Scheme() == "https"
 
-			// 检查用户是否已认证
-			authenticated, ok := session.Values["authenticated"].(bool)
-			if !ok || !authenticated {
-				return echo.NewHTTPError(http.StatusUnauthorized, "用户未认证")
-			}
+	return &opts
+}
 
-			// 检查session中的用户信息
-			userID, ok := session.Values["user_id"].(string)
-			if !ok || userID == "" {
-				return echo.NewHTTPError(http.StatusUnauthorized, "session中缺少用户信息")
-			}
+// Check 检查session存储是否可用：使用当前密钥对一个测试值编码再解码.
+func (s *SessionMiddleware) Check(_ context.Context) error {
+	const probe = "health-check"
+
+	encoded, err := securecookie.EncodeMulti(s.name, probe, s.Store.Codecs...)
+	if err != nil {
+		return fmt.Errorf("session编码失败: %w", err)
+	}
+
+	var decoded string
+	if err := securecookie.DecodeMulti(s.name, encoded, &decoded, s.Store.Codecs...); err != nil {
+		return fmt.Errorf("session解码失败: %w", err)
+	}
+
+	if decoded != probe {
+		return errors.New("session编解码结果不一致")
+	}
 
-			username, _ := session.Values["username"].(string) //nolint:errcheck
-			email, _ := session.Values["email"].(string)       //nolint:errcheck
+	return nil
+}
 
-			// 将用户信息存储到context中
-			c.Set("user_id", userID)
-			c.Set("username", username)
-			c.Set("email", email)
+// SessionAuth session认证中间件，会话超过有效期或无活动超时后要求重新登录；开启滑动续期时按需续期.
+func (s *SessionMiddleware) SessionAuth() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !s.authenticate(c) {
+				return apperr.ErrUnauthenticated
+			}
 
 			return next(c)
 		}
@@ -118,31 +307,431 @@ func (s *SessionMiddleware) SessionAuth() echo.MiddlewareFunc {
 func (s *SessionMiddleware) OptionalSessionAuth() echo.MiddlewareFunc {
 	return func(next echo.HandlerFunc) echo.HandlerFunc {
 		return func(c echo.Context) error {
-			session, err := s.Store.Get(c.Request(), "user-session")
-			if err == nil {
-				// 检查用户是否已认证
-				if authenticated, ok := session.Values["authenticated"].(bool); ok && authenticated {
-					// 提取用户信息
-					if userID, ok := session.Values["user_id"].(string); ok && userID != "" {
-						username, _ := session.Values["username"].(string) //nolint:errcheck
-						email, _ := session.Values["email"].(string)       //nolint:errcheck
-
-						// 将用户信息存储到context中
-						c.Set("user_id", userID)
-						c.Set("username", username)
-						c.Set("email", email)
-					}
-				}
+			s.authenticate(c)
+			return next(c)
+		}
+	}
+}
+
+// authenticate 校验session并将用户信息存储到context中，返回是否已登录.
+// 会话不存在或已超时时尝试用持久登录令牌恢复登录.
+func (s *SessionMiddleware) authenticate(c echo.Context) bool {
+	return s.authenticateSession(c) || s.restore(c)
+}
+
+// authenticateSession 校验session并将用户信息存储到context中.
+// 会话已超时时删除Cookie；距上次记录活动超过 lastSeenInterval 或需要续期时重新写入Cookie.
+func (s *SessionMiddleware) authenticateSession(c echo.Context) bool {
+	session, err := s.Store.Get(c.Request(), s.name)
+	if err != nil {
+		return false
+	}
+
+	// 检查用户是否已认证
+	authenticated, ok := session.Values["authenticated"].(bool)
+	if !ok || !authenticated {
+		return false
+	}
+
+	// 检查session中的用户信息
+	userID, ok := session.Values["user_id"].(string)
+	if !ok || userID == "" {
+		return false
+	}
+
+	createdAt, ok := session.Values["created_at"].(int64)
+	if !ok {
+		return false
+	}
+
+	// 旧版本创建的会话没有最后活动时间，以创建时间代替
+	lastSeenAt, ok := session.Values["last_seen_at"].(int64)
+	if !ok {
+		lastSeenAt = createdAt
+	}
+
+	now := time.Now()
+	expiresAt := s.expiresAt(session, time.Unix(createdAt, 0))
+
+	if s.expired(now, expiresAt, time.Unix(lastSeenAt, 0)) {
+		if err := s.expire(c, session); err != nil {
+			s.logger.ErrorContext(c.Request().Context(), "清除超时session失败", "error", err)
+		}
+
+		s.recorder.SessionDestroyed(time.Unix(createdAt, 0))
+
+		return false
+	}
+
+	if revoked, err := s.revoked(c, session); err != nil || revoked {
+		// 查询失败时保留Cookie，恢复后会话仍然有效
+		if revoked {
+			if err := s.expire(c, session); err != nil {
+				s.logger.ErrorContext(c.Request().Context(), "清除已注销session失败", "error", err)
 			}
+		}
 
-			return next(c)
+		return false
+	}
+
+	touch := s.idle > 0 && now.Sub(time.Unix(lastSeenAt, 0)) >= lastSeenInterval
+	refresh := s.shouldRefresh(now, expiresAt) && s.extend(session, now, time.Unix(createdAt, 0), expiresAt)
+
+	if touch || refresh {
+		session.Values["last_seen_at"] = now.Unix()
+		session.Options = s.options(c)
+
+		// 更新失败不影响本次请求，只会使会话提前过期
+		if err := session.Save(c.Request(), c.Response()); err != nil {
+			s.logger.ErrorContext(c.Request().Context(), "更新session失败", "error", err)
 		}
 	}
+
+	username, _ := session.Values["username"].(string)    //nolint:errcheck
+	email, _ := session.Values["email"].(string)          //nolint:errcheck
+	sessionID, _ := session.Values["session_id"].(string) //nolint:errcheck
+
+	setUser(c, userID, username, email)
+	c.Set("session_id", sessionID)
+
+	return true
+}
+
+// UserID 返回当前请求的登录用户ID，未登录时返回空字符串.
+//
+// 已经过认证中间件时直接读取context；否则只解码并校验session，不续期、不写Cookie，也不用持久登录令牌
+// 恢复登录，供注册在认证中间件之前的中间件（如限流）识别用户.
+func (s *SessionMiddleware) UserID(c echo.Context) string {
+	if userID := GetUserIDFromSession(c); userID != "" {
+		return userID
+	}
+
+	session := s.authenticatedSession(c)
+	if session == nil {
+		return ""
+	}
+
+	userID, _ := session.Values["user_id"].(string) //nolint:errcheck
+
+	return userID
 }
 
-// RefreshSession 刷新session（延长过期时间）.
+// SessionID 返回当前请求已校验的登录会话ID，未登录或旧版本创建的会话没有会话ID时返回空字符串.
+// 与 UserID 一样不续期、不写Cookie，供限流等中间件按登录凭证区分请求.
+func (s *SessionMiddleware) SessionID(c echo.Context) string {
+	if sessionID, ok := c.Get("session_id").(string); ok && sessionID != "" {
+		return sessionID
+	}
+
+	session := s.authenticatedSession(c)
+	if session == nil {
+		return ""
+	}
+
+	sessionID, _ := session.Values["session_id"].(string) //nolint:errcheck
+
+	return sessionID
+}
+
+// authenticatedSession 解码并校验请求中的登录会话：已认证、未超时且未注销，不满足时返回 nil.
+func (s *SessionMiddleware) authenticatedSession(c echo.Context) *sessions.Session {
+	session, err := s.Store.Get(c.Request(), s.name)
+	if err != nil {
+		return nil
+	}
+
+	if authenticated, ok := session.Values["authenticated"].(bool); !ok || !authenticated {
+		return nil
+	}
+
+	if userID, ok := session.Values["user_id"].(string); !ok || userID == "" {
+		return nil
+	}
+
+	createdAt, ok := session.Values["created_at"].(int64)
+	if !ok {
+		return nil
+	}
+
+	lastSeenAt, ok := session.Values["last_seen_at"].(int64)
+	if !ok {
+		lastSeenAt = createdAt
+	}
+
+	if s.expired(time.Now(), s.expiresAt(session, time.Unix(createdAt, 0)), time.Unix(lastSeenAt, 0)) {
+		return nil
+	}
+
+	if revoked, err := s.revoked(c, session); err != nil || revoked {
+		return nil
+	}
+
+	return session
+}
+
+// restore 使用持久登录令牌恢复登录：校验并轮换令牌，创建新的session. 令牌无效时删除Cookie.
+func (s *SessionMiddleware) restore(c echo.Context) bool {
+	if s.tokens == nil {
+		return false
+	}
+
+	cookie, err := c.Cookie(s.rememberName())
+	if err != nil || cookie.Value == "" {
+		return false
+	}
+
+	ctx := c.Request().Context()
+
+	user, token, err := s.tokens.Authenticate(ctx, cookie.Value, loginClient(c))
+	if err != nil {
+		if errors.Is(err, apperr.ErrUnauthenticated) {
+			s.Forget(c)
+		} else {
+			// 数据库暂时不可用等错误保留Cookie，下次请求再试
+			s.logger.ErrorContext(ctx, "校验持久登录令牌失败", "error", err)
+		}
+
+		return false
+	}
+
+	// 并发请求中只有一个会拿到新令牌，其余请求不改写Cookie
+	if token != "" {
+		s.setRememberCookie(c, token)
+	}
+
+	series, _, _ := model.ParseLoginToken(cookie.Value)
+
+	if err := s.startSession(c, user.ID, user.Username, user.Email, series); err != nil {
+		s.logger.ErrorContext(ctx, "恢复登录时创建session失败", "error", err)
+		return false
+	}
+
+	s.logger.InfoContext(ctx, "使用持久登录令牌恢复登录", "login_user_id", user.ID)
+	setUser(c, user.ID, user.Username, user.Email)
+
+	return true
+}
+
+// Remember 为用户签发持久登录令牌并写入Cookie，未开启"记住我"时不做任何事.
+func (s *SessionMiddleware) Remember(c echo.Context, userID string) error {
+	if s.tokens == nil {
+		return nil
+	}
+
+	token, err := s.tokens.Issue(c.Request().Context(), userID, loginClient(c))
+	if err != nil {
+		return err
+	}
+
+	s.setRememberCookie(c, token)
+
+	return nil
+}
+
+// Forget 让浏览器删除持久登录令牌Cookie.
+func (s *SessionMiddleware) Forget(c echo.Context) {
+	cookie := s.rememberCookie(c, "")
+	cookie.MaxAge = -1
+
+	c.SetCookie(cookie)
+}
+
+// LoginTokenID 返回当前浏览器持有的持久登录令牌的系列ID，没有时返回空字符串.
+func (s *SessionMiddleware) LoginTokenID(c echo.Context) string {
+	cookie, err := c.Cookie(s.rememberName())
+	if err != nil {
+		return ""
+	}
+
+	id, _, _ := model.ParseLoginToken(cookie.Value)
+
+	return id
+}
+
+// setRememberCookie 写入持久登录令牌Cookie.
+func (s *SessionMiddleware) setRememberCookie(c echo.Context, token string) {
+	cookie := s.rememberCookie(c, token)
+	cookie.MaxAge = int(s.rememberTTL.Seconds())
+
+	c.SetCookie(cookie)
+}
+
+// rememberCookie 返回与会话Cookie属性一致的持久登录令牌Cookie.
+func (s *SessionMiddleware) rememberCookie(c echo.Context, value string) *http.Cookie {
+	opts := s.options(c)
+
+	return &http.Cookie{
+		Name:     s.rememberName(),
+		Value:    value,
+		Path:     opts.Path,
+		Domain:   opts.Domain,
+		HttpOnly: true,
+		Secure:   opts.Secure,
+		SameSite: opts.SameSite,
+	}
+}
+
+// rememberName 返回持久登录令牌Cookie的名称.
+func (s *SessionMiddleware) rememberName() string {
+	return s.name + rememberCookieSuffix
+}
+
+// loginClient 返回请求的客户端信息.
+func loginClient(c echo.Context) model.LoginClient {
+	return model.LoginClient{
+		UserAgent: c.Request().UserAgent(),
+		IP:        c.RealIP(),
+	}
+}
+
+// isNotFound 判断错误是否为资源不存在类的应用错误.
+func isNotFound(err error) bool {
+	appErr, ok := apperr.As(err)
+	return ok && appErr.Kind == apperr.KindNotFound
+}
+
+// setUser 将用户信息存储到context中.
+func setUser(c echo.Context, userID, username, email string) {
+	c.Set("user_id", userID)
+	c.Set("username", username)
+	c.Set("email", email)
+	c.SetRequest(c.Request().WithContext(reqctx.WithUserID(c.Request().Context(), userID)))
+}
+
+// revoke 记录会话已注销，未登录的会话不需要记录. 记录失败只写日志：浏览器中的Cookie随后会被删除或替换.
+func (s *SessionMiddleware) revoke(c echo.Context, session *sessions.Session) {
+	if s.revocations == nil {
+		return
+	}
+
+	sessionID, _ := session.Values["session_id"].(string) //nolint:errcheck
+	userID, _ := session.Values["user_id"].(string)       //nolint:errcheck
+
+	createdAt, ok := session.Values["created_at"].(int64)
+	if !ok || sessionID == "" || userID == "" {
+		return
+	}
+
+	ctx := c.Request().Context()
+
+	if err := s.revocations.Revoke(ctx, sessionID, userID, s.lifetimeEnd(time.Unix(createdAt, 0))); err != nil {
+		s.logger.ErrorContext(ctx, "记录已注销会话失败", "error", err)
+	}
+}
+
+// revoked 判断会话是否已注销，同一请求中对同一会话只查询一次. 旧版本创建的会话没有会话ID，不检查.
+//
+// 由持久登录令牌恢复的会话在令牌系列被吊销后也视为已注销：令牌被盗用时，盗用者已经恢复的会话随系列一起失效.
+func (s *SessionMiddleware) revoked(c echo.Context, session *sessions.Session) (bool, error) {
+	sessionID, _ := session.Values["session_id"].(string) //nolint:errcheck
+	if sessionID == "" {
+		return false, nil
+	}
+
+	if revoked, ok := c.Get(revokedKeyPrefix + sessionID).(bool); ok {
+		return revoked, nil
+	}
+
+	ctx := c.Request().Context()
+	revoked := false
+
+	if s.revocations != nil {
+		var err error
+
+		revoked, err = s.revocations.IsRevoked(ctx, sessionID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "查询会话是否已注销失败", "error", err)
+			return false, err
+		}
+	}
+
+	if series, _ := session.Values["login_series"].(string); !revoked && series != "" && s.tokens != nil { //nolint:errcheck
+		exists, err := s.tokens.Exists(ctx, series)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "查询持久登录令牌是否已吊销失败", "error", err)
+			return false, err
+		}
+
+		revoked = !exists
+	}
+
+	c.Set(revokedKeyPrefix+sessionID, revoked)
+
+	return revoked, nil
+}
+
+// lifetimeEnd 返回会话的最晚到期时间：开启滑动续期时为登录时间加续期上限，否则为登录时间加有效期.
+// 同一会话的多份Cookie可能各自续期，注销记录需要保留到其中最晚的到期时间.
+func (s *SessionMiddleware) lifetimeEnd(createdAt time.Time) time.Time {
+	if s.refreshAfter > 0 && s.maxLifetime > s.absolute {
+		return createdAt.Add(s.maxLifetime)
+	}
+
+	return createdAt.Add(s.absolute)
+}
+
+// expired 判断会话是否超过有效期或无活动超时.
+func (s *SessionMiddleware) expired(now, expiresAt, lastSeenAt time.Time) bool {
+	if now.After(expiresAt) {
+		return true
+	}
+
+	return s.idle > 0 && now.Sub(lastSeenAt) > s.idle
+}
+
+// expiresAt 返回会话的到期时间.
+//
+// 未开启滑动续期时为登录时间加有效期；开启时使用会话中记录的到期时间（旧版本创建的会话没有记录，
+// 以登录时间加有效期代替），且不超过登录时间加续期上限.
+func (s *SessionMiddleware) expiresAt(session *sessions.Session, createdAt time.Time) time.Time {
+	deadline := createdAt.Add(s.absolute)
+	if s.refreshAfter <= 0 {
+		return deadline
+	}
+
+	if v, ok := session.Values["expires_at"].(int64); ok {
+		deadline = time.Unix(v, 0)
+	}
+
+	if limit := createdAt.Add(s.maxLifetime); deadline.After(limit) {
+		deadline = limit
+	}
+
+	return deadline
+}
+
+// shouldRefresh 判断会话是否已过去有效期的 refreshAfter 比例，需要续期.
+func (s *SessionMiddleware) shouldRefresh(now, expiresAt time.Time) bool {
+	if s.refreshAfter <= 0 {
+		return false
+	}
+
+	issuedAt := expiresAt.Add(-s.absolute)
+	threshold := time.Duration(float64(s.absolute) * s.refreshAfter)
+
+	return now.Sub(issuedAt) >= threshold
+}
+
+// extend 将会话到期时间延长为当前时间加有效期，但不超过登录时间加续期上限，返回到期时间是否有变化.
+func (s *SessionMiddleware) extend(session *sessions.Session, now, createdAt, expiresAt time.Time) bool {
+	deadline := now.Add(s.absolute)
+	if limit := createdAt.Add(s.maxLifetime); deadline.After(limit) {
+		deadline = limit
+	}
+
+	if !deadline.After(expiresAt) {
+		return false
+	}
+
+	session.Values["expires_at"] = deadline.Unix()
+
+	return true
+}
+
+// RefreshSession 刷新session：重新写入Cookie以延长Cookie有效期并记录活动时间.
+// 开启滑动续期时同时延长会话到期时间（不超过续期上限），否则不改变登录后的有效期.
 func (s *SessionMiddleware) RefreshSession(c echo.Context) error {
-	session, err := s.Store.Get(c.Request(), "user-session")
+	session, err := s.Store.Get(c.Request(), s.name)
 	if err != nil {
 		return err
 	}
@@ -150,16 +739,36 @@ func (s *SessionMiddleware) RefreshSession(c echo.Context) error {
 	// 检查用户是否已认证
 	authenticated, ok := session.Values["authenticated"].(bool)
 	if !ok || !authenticated {
-		return echo.NewHTTPError(http.StatusUnauthorized, "用户未认证")
+		return apperr.ErrUnauthenticated
 	}
 
-	// 更新创建时间
-	session.Values["created_at"] = time.Now().Unix()
+	createdAt, ok := session.Values["created_at"].(int64)
+	if !ok {
+		return apperr.ErrUnauthenticated
+	}
+
+	now := time.Now()
+	if s.refreshAfter > 0 {
+		s.extend(session, now, time.Unix(createdAt, 0), s.expiresAt(session, time.Unix(createdAt, 0)))
+	}
+
+	session.Values["last_seen_at"] = now.Unix()
+	session.Options = s.options(c)
 
 	// 保存session
 	return session.Save(c.Request(), c.Response())
 }
 
+// newSessionID 生成随机会话ID.
+func newSessionID() (string, error) {
+	b := make([]byte, sessionIDSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+
+	return base64.RawURLEncoding.EncodeToString(b), nil
+}
+
 // GetUserIDFromSession 从session中获取用户ID.
 func GetUserIDFromSession(c echo.Context) string {
 	userID := c.Get("user_id")
@@ -209,7 +818,7 @@ func GetEmailFromSession(c echo.Context) string {
 func ExtractUserIDFromSession(c echo.Context) (string, error) {
 	userID, ok := c.Get("user_id").(string)
 	if !ok || userID == "" {
-		return "", echo.NewHTTPError(http.StatusUnauthorized, "用户ID未找到")
+		return "", apperr.ErrUnauthenticated
 	}
 
 	return userID, nil
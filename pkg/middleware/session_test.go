@@ -0,0 +1,178 @@
+package middleware
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go-react-template/configs"
+	"go-react-template/pkg/apperr"
+	"go-react-template/pkg/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+// fakeLoginTokens 在内存中保存令牌系列，每个系列只接受一个令牌.
+type fakeLoginTokens struct {
+	tokens map[string]string // 系列ID -> 当前令牌
+	user   *model.User
+}
+
+func (f *fakeLoginTokens) Issue(context.Context, string, model.LoginClient) (string, error) {
+	return "", nil
+}
+
+func (f *fakeLoginTokens) Authenticate(_ context.Context, token string, _ model.LoginClient) (*model.User, string, error) {
+	id, _, _ := model.ParseLoginToken(token)
+
+	current, ok := f.tokens[id]
+	if !ok {
+		return nil, "", apperr.ErrUnauthenticated
+	}
+
+	if current != token {
+		// 重复使用已轮换的令牌，吊销整个系列
+		delete(f.tokens, id)
+		return nil, "", apperr.ErrUnauthenticated
+	}
+
+	f.tokens[id] = token + "x"
+
+	return f.user, f.tokens[id], nil
+}
+
+func (f *fakeLoginTokens) Exists(_ context.Context, id string) (bool, error) {
+	_, ok := f.tokens[id]
+	return ok, nil
+}
+
+func (f *fakeLoginTokens) Revoke(_ context.Context, _, id string) error {
+	delete(f.tokens, id)
+	return nil
+}
+
+// authenticateWith 携带 cookies 发起一次需要登录的请求，返回是否已登录和响应写入的Cookie.
+func authenticateWith(e *echo.Echo, s *SessionMiddleware, cookies ...*http.Cookie) (bool, []*http.Cookie) {
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/user/profile", nil)
+	for _, cookie := range cookies {
+		req.AddCookie(cookie)
+	}
+
+	rec := httptest.NewRecorder()
+	ok := s.authenticate(e.NewContext(req, rec))
+
+	return ok, rec.Result().Cookies()
+}
+
+// findCookie 按名称查找Cookie.
+func findCookie(cookies []*http.Cookie, name string) *http.Cookie {
+	for _, cookie := range cookies {
+		if cookie.Name == name {
+			return cookie
+		}
+	}
+
+	return nil
+}
+
+func TestRestoredSessionRevokedWithSeries(t *testing.T) {
+	e := echo.New()
+	tokens := &fakeLoginTokens{
+		tokens: map[string]string{"series": "series.secret"},
+		user:   &model.User{ID: "user-1", Username: "alice"},
+	}
+	s := NewSessionMiddleware(configs.Default().Session, false, nopSessionRecorder{}, tokens, nil, slog.New(slog.DiscardHandler))
+
+	// 盗用者用偷来的令牌恢复登录，令牌被轮换
+	stolen := &http.Cookie{Name: s.rememberName(), Value: "series.secret"}
+
+	ok, cookies := authenticateWith(e, s, stolen)
+	if !ok {
+		t.Fatal("有效的持久登录令牌应恢复登录")
+	}
+
+	thiefSession := findCookie(cookies, s.name)
+	if thiefSession == nil {
+		t.Fatal("恢复登录应写入会话Cookie")
+	}
+
+	if ok, _ := authenticateWith(e, s, thiefSession); !ok {
+		t.Fatal("系列未被吊销时恢复的会话应有效")
+	}
+
+	// 用户使用已被轮换掉的令牌，系列因重复使用被吊销
+	if ok, _ := authenticateWith(e, s, stolen); ok {
+		t.Fatal("重复使用的令牌不应恢复登录")
+	}
+
+	ok, cookies = authenticateWith(e, s, thiefSession)
+	if ok {
+		t.Fatal("系列被吊销后，由该系列恢复的会话应失效")
+	}
+
+	if cookie := findCookie(cookies, s.name); cookie == nil || cookie.MaxAge >= 0 {
+		t.Fatal("失效的会话应删除Cookie")
+	}
+}
+
+func TestLoginSessionNotBoundToSeries(t *testing.T) {
+	e := echo.New()
+	tokens := &fakeLoginTokens{tokens: map[string]string{}}
+	s := NewSessionMiddleware(configs.Default().Session, false, nopSessionRecorder{}, tokens, nil, slog.New(slog.DiscardHandler))
+
+	rec := httptest.NewRecorder()
+	if err := s.CreateSession(e.NewContext(httptest.NewRequest(http.MethodPost, "/api/v1/auth/login", nil), rec), &model.User{ID: "user-1"}); err != nil {
+		t.Fatalf("创建会话失败: %v", err)
+	}
+
+	session := findCookie(rec.Result().Cookies(), s.name)
+	if session == nil {
+		t.Fatal("登录应写入会话Cookie")
+	}
+
+	if ok, _ := authenticateWith(e, s, session); !ok {
+		t.Fatal("登录创建的会话不依赖持久登录令牌")
+	}
+}
+
+func TestSessionID(t *testing.T) {
+	e := echo.New()
+	tokens := &fakeLoginTokens{
+		tokens: map[string]string{"series": "series.secret"},
+		user:   &model.User{ID: "user-1"},
+	}
+	s := NewSessionMiddleware(configs.Default().Session, false, nopSessionRecorder{}, tokens, nil, slog.New(slog.DiscardHandler))
+
+	sessionIDWith := func(cookies ...*http.Cookie) string {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/user/profile", nil)
+		for _, cookie := range cookies {
+			req.AddCookie(cookie)
+		}
+
+		return s.SessionID(e.NewContext(req, httptest.NewRecorder()))
+	}
+
+	if id := sessionIDWith(); id != "" {
+		t.Fatalf("没有会话时 SessionID = %q, want 空", id)
+	}
+
+	_, anonymous := issueCSRFToken(t, e, s)
+	if id := sessionIDWith(anonymous...); id != "" {
+		t.Fatalf("未登录的会话 SessionID = %q, want 空", id)
+	}
+
+	_, cookies := authenticateWith(e, s, &http.Cookie{Name: s.rememberName(), Value: "series.secret"})
+	session := findCookie(cookies, s.name)
+
+	if id := sessionIDWith(session); id == "" {
+		t.Fatal("登录的会话应返回会话ID")
+	}
+
+	delete(tokens.tokens, "series")
+
+	if id := sessionIDWith(session); id != "" {
+		t.Fatalf("已注销的会话 SessionID = %q, want 空", id)
+	}
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// LoginToken "记住我"持久登录令牌.
+//
+// 每次勾选"记住我"的登录产生一个系列，ID 即系列ID；令牌每次使用后轮换，数据库只保存令牌的哈希.
+type LoginToken struct {
+	ID           string    `json:"id" gorm:"type:char(36);primarykey"`
+	UserID       string    `json:"user_id" gorm:"type:char(36);index;not null"`
+	TokenHash    string    `json:"-" gorm:"size:64;not null;comment:当前令牌的SHA-256哈希"`
+	PreviousHash string    `json:"-" gorm:"size:64;comment:轮换前令牌的SHA-256哈希，用于识别并发请求"`
+	UserAgent    string    `json:"user_agent" gorm:"size:255;comment:最后使用的浏览器"`
+	IP           string    `json:"ip" gorm:"size:45;comment:最后使用的IP"`
+	LastUsedAt   time.Time `json:"last_used_at"`
+	ExpiresAt    time.Time `json:"expires_at" gorm:"index"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// TableName 指定表名.
+func (LoginToken) TableName() string {
+	return "login_tokens"
+}
+
+// BeforeCreate 在创建前生成系列ID.
+func (t *LoginToken) BeforeCreate(_ *gorm.DB) error {
+	if t.ID == "" {
+		t.ID = uuid.New().String()
+	}
+
+	return nil
+}
+
+// LoginTokenValue 组合系列ID和随机串，得到写入Cookie的令牌.
+func LoginTokenValue(id, secret string) string {
+	return id + "." + secret
+}
+
+// ParseLoginToken 拆分Cookie中的令牌，返回系列ID和随机串.
+func ParseLoginToken(token string) (id, secret string, ok bool) {
+	id, secret, ok = strings.Cut(token, ".")
+	if !ok || id == "" || secret == "" {
+		return "", "", false
+	}
+
+	return id, secret, true
+}
+
+// LoginClient 使用令牌的客户端信息，用于在令牌列表中区分不同设备.
+type LoginClient struct {
+	UserAgent string
+	IP        string
+}
+
+// LoginTokenResponse 持久登录令牌响应结构（不包含令牌哈希）.
+type LoginTokenResponse struct {
+	ID         string    `json:"id"`
+	UserAgent  string    `json:"user_agent"`
+	IP         string    `json:"ip"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+	ExpiresAt  time.Time `json:"expires_at"`
+	Current    bool      `json:"current"` // 是否为当前浏览器持有的令牌
+}
+
+// ToResponse 将LoginToken转换为LoginTokenResponse.
+func (t *LoginToken) ToResponse(current bool) LoginTokenResponse {
+	return LoginTokenResponse{
+		ID:         t.ID,
+		UserAgent:  t.UserAgent,
+		IP:         t.IP,
+		CreatedAt:  t.CreatedAt,
+		LastUsedAt: t.LastUsedAt,
+		ExpiresAt:  t.ExpiresAt,
+		Current:    current,
+	}
+}
@@ -0,0 +1,19 @@
+package model
+
+import "time"
+
+// RevokedSession 已注销的会话.
+//
+// 会话数据保存在浏览器的Cookie中，注销或更换会话ID后，旧Cookie在到期前仍能通过签名校验；
+// 服务端记录这些会话ID，认证时拒绝. 会话原本的到期时间过后记录即可删除.
+type RevokedSession struct {
+	SessionID string    `json:"session_id" gorm:"size:64;primarykey"`
+	UserID    string    `json:"user_id" gorm:"type:char(36);not null"`
+	ExpiresAt time.Time `json:"expires_at" gorm:"index;comment:会话原本的最晚到期时间，之后记录可以删除"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TableName 指定表名.
+func (RevokedSession) TableName() string {
+	return "revoked_sessions"
+}
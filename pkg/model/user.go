@@ -34,6 +34,11 @@ const (
 	LoginTypeGoogle LoginType = "google" // Google第三方登录
 )
 
+// EnumValues 返回所有登录类型，用于生成接口文档.
+func (LoginType) EnumValues() []string {
+	return []string{string(LoginTypeLocal), string(LoginTypeGoogle)}
+}
+
 // TableName 指定表名.
 func (User) TableName() string {
 	return "users"
@@ -41,15 +46,16 @@ func (User) TableName() string {
 
 // UserRegisterRequest 用户注册请求结构.
 type UserRegisterRequest struct {
-	Username string `json:"username" validate:"required,min=3,max=50"`
+	Username string `json:"username" validate:"required,min=3,max=50,username,notreserved"`
 	Email    string `json:"email" validate:"required,email"`
 	Password string `json:"password" validate:"required,min=6"`
 }
 
 // UserLoginRequest 用户登录请求结构.
 type UserLoginRequest struct {
-	Email    string `json:"email" validate:"required,email"`
-	Password string `json:"password" validate:"required"`
+	Email      string `json:"email" validate:"required,email"`
+	Password   string `json:"password" validate:"required"`
+	RememberMe bool   `json:"remember_me,omitempty"` // 同时签发持久登录令牌，会话过期后自动恢复登录
 }
 
 // UserResponse 用户响应结构（不包含密码）.
@@ -60,17 +66,19 @@ type UserResponse struct {
 	AvatarURL string    `json:"avatar_url"`
 	LoginType LoginType `json:"login_type"`
 	Bio       string    `json:"bio"`
+	CreatedAt time.Time `json:"created_at"`
 }
 
 // UserChangePasswordRequest 用户更改密码请求结构.
 type UserChangePasswordRequest struct {
 	OldPassword string `json:"old_password" validate:"required"`
-	NewPassword string `json:"new_password" validate:"required,min=6"`
+	NewPassword string `json:"new_password" validate:"required,min=6,nefield=OldPassword"`
 }
 
 // GoogleLoginRequest Google登录请求结构.
 type GoogleLoginRequest struct {
-	IDToken string `json:"id_token" validate:"required"`
+	IDToken    string `json:"id_token" validate:"required"`
+	RememberMe bool   `json:"remember_me,omitempty"` // 同时签发持久登录令牌，会话过期后自动恢复登录
 }
 
 // ToResponse 将User转换为UserResponse.
@@ -82,12 +90,13 @@ func (u *User) ToResponse() UserResponse {
 		AvatarURL: u.AvatarURL,
 		LoginType: u.LoginType,
 		Bio:       u.Bio,
+		CreatedAt: u.CreatedAt,
 	}
 }
 
 // UserUpdateProfileRequest 用户更新个人资料请求结构.
 type UserUpdateProfileRequest struct {
-	Username string `json:"username" validate:"omitempty,min=3,max=50"`
+	Username string `json:"username" validate:"omitempty,min=3,max=50,username,notreserved"`
 	Email    string `json:"email" validate:"omitempty,email"`
 	Bio      string `json:"bio" validate:"omitempty,max=500"`
 }
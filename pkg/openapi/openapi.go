@@ -0,0 +1,292 @@
+// Package openapi 根据接口声明和 Go 请求/响应类型生成 OpenAPI 3.1 文档，并检查路由是否都有文档
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Version 生成的文档使用的 OpenAPI 版本.
+const Version = "3.1.0"
+
+// sessionCookieScheme 会话 Cookie 认证方案名称.
+const sessionCookieScheme = "sessionCookie"
+
+// csrfTokenScheme CSRF 令牌请求头方案名称.
+const csrfTokenScheme = "csrfToken"
+
+// pathParam 匹配 Echo 路由中的路径参数，如 :id.
+var pathParam = regexp.MustCompile(`:([A-Za-z0-9_]+)`)
+
+// Operation 一个接口的文档声明.
+type Operation struct {
+	Method      string
+	Path        string // Echo 路由格式，路径参数写作 :id
+	OperationID string // 唯一的操作名，生成客户端代码时用作函数名
+	Tag         string
+	Summary     string
+	Description string
+	Auth        bool        // 需要登录
+	Request     interface{} // 请求体类型的零值，nil 表示没有请求体
+	Response    interface{} // 成功响应中 data 的类型零值，nil 表示 data 为 null
+	Raw         bool        // 响应不使用 {code,data,message} 统一格式，Response 为完整的响应体类型
+	Errors      []int       // 可能返回的错误状态码
+	Deprecated  bool        // 已弃用，保留只为兼容旧客户端
+}
+
+// Info 文档基本信息.
+type Info struct {
+	Title         string
+	Version       string
+	Description   string
+	SessionCookie string // 登录会话的 Cookie 名称
+	CSRFHeader    string // 除 GET/HEAD/OPTIONS 外的接口需要携带的 CSRF 令牌请求头，为空表示不需要
+}
+
+// Spec OpenAPI 文档构建器.
+type Spec struct {
+	info          Info
+	errorResponse reflect.Type
+	operations    []Operation
+	schemas       map[string]Schema
+	propertyOrder map[string][]string // 具名结构体的属性按字段定义顺序排列，用于生成代码
+}
+
+// New 创建文档构建器. errorResponse 为失败响应体类型的零值.
+func New(info Info, errorResponse interface{}) *Spec {
+	return &Spec{
+		info:          info,
+		errorResponse: reflect.TypeOf(errorResponse),
+	}
+}
+
+// Add 添加接口声明.
+func (s *Spec) Add(ops ...Operation) {
+	s.operations = append(s.operations, ops...)
+}
+
+// Operations 返回所有接口声明.
+func (s *Spec) Operations() []Operation {
+	return s.operations
+}
+
+// Document 生成 OpenAPI 文档.
+func (s *Spec) Document() map[string]interface{} {
+	s.schemas = make(map[string]Schema)
+	s.propertyOrder = make(map[string][]string)
+
+	paths := make(map[string]map[string]interface{})
+	for _, op := range s.operations {
+		path := pathParam.ReplaceAllString(op.Path, "{$1}")
+		if paths[path] == nil {
+			paths[path] = make(map[string]interface{})
+		}
+
+		paths[path][strings.ToLower(op.Method)] = s.operation(op)
+	}
+
+	schemes := map[string]interface{}{
+		sessionCookieScheme: map[string]interface{}{
+			"type": "apiKey",
+			"in":   "cookie",
+			"name": s.info.SessionCookie,
+		},
+	}
+
+	if s.info.CSRFHeader != "" {
+		schemes[csrfTokenScheme] = map[string]interface{}{
+			"type": "apiKey",
+			"in":   "header",
+			"name": s.info.CSRFHeader,
+		}
+	}
+
+	return map[string]interface{}{
+		"openapi": Version,
+		"info": map[string]interface{}{
+			"title":       s.info.Title,
+			"version":     s.info.Version,
+			"description": s.info.Description,
+		},
+		"paths": paths,
+		"components": map[string]interface{}{
+			"schemas":         s.schemas,
+			"securitySchemes": schemes,
+		},
+	}
+}
+
+// JSON 生成 JSON 格式的文档.
+func (s *Spec) JSON() ([]byte, error) {
+	return json.MarshalIndent(s.Document(), "", "  ")
+}
+
+// operation 生成单个接口的 Operation Object.
+func (s *Spec) operation(op Operation) map[string]interface{} {
+	result := map[string]interface{}{
+		"operationId": op.OperationID,
+		"summary":     op.Summary,
+		"responses":   s.responses(op),
+	}
+
+	if op.Tag != "" {
+		result["tags"] = []string{op.Tag}
+	}
+
+	if op.Description != "" {
+		result["description"] = op.Description
+	}
+
+	if op.Deprecated {
+		result["deprecated"] = true
+	}
+
+	if params := pathParam.FindAllStringSubmatch(op.Path, -1); len(params) > 0 {
+		parameters := make([]interface{}, len(params))
+		for i, p := range params {
+			parameters[i] = map[string]interface{}{
+				"name":     p[1],
+				"in":       "path",
+				"required": true,
+				"schema":   Schema{"type": "string"},
+			}
+		}
+
+		result["parameters"] = parameters
+	}
+
+	if op.Request != nil {
+		result["requestBody"] = map[string]interface{}{
+			"required": true,
+			"content":  jsonContent(s.schemaOf(reflect.TypeOf(op.Request))),
+		}
+	}
+
+	requirement := make(map[string]interface{})
+	if op.Auth {
+		requirement[sessionCookieScheme] = []string{}
+	}
+
+	if s.requiresCSRF(op) {
+		requirement[csrfTokenScheme] = []string{}
+	}
+
+	if len(requirement) > 0 {
+		result["security"] = []interface{}{requirement}
+	}
+
+	return result
+}
+
+// requiresCSRF 判断接口是否需要携带 CSRF 令牌.
+func (s *Spec) requiresCSRF(op Operation) bool {
+	if s.info.CSRFHeader == "" {
+		return false
+	}
+
+	switch op.Method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return false
+	default:
+		return true
+	}
+}
+
+// responses 生成成功响应和声明的错误响应.
+func (s *Spec) responses(op Operation) map[string]interface{} {
+	var body Schema
+
+	switch {
+	case op.Raw:
+		body = s.schemaOf(reflect.TypeOf(op.Response))
+	default:
+		data := Schema{"type": "null"}
+		if op.Response != nil {
+			data = s.schemaOf(reflect.TypeOf(op.Response))
+		}
+
+		body = Schema{
+			"type":     "object",
+			"required": []string{"code", "data", "message"},
+			"properties": map[string]interface{}{
+				"code":    Schema{"type": "integer", "const": 0},
+				"data":    data,
+				"message": Schema{"type": "string"},
+			},
+		}
+	}
+
+	responses := map[string]interface{}{
+		"200": map[string]interface{}{
+			"description": http.StatusText(http.StatusOK),
+			"content":     jsonContent(body),
+		},
+	}
+
+	errors := op.Errors
+	if op.Auth {
+		errors = append([]int{http.StatusUnauthorized}, errors...)
+	}
+
+	if s.requiresCSRF(op) {
+		errors = append(errors, http.StatusForbidden)
+	}
+
+	for _, status := range errors {
+		responses[strconv.Itoa(status)] = map[string]interface{}{
+			"description": http.StatusText(status),
+			"content":     jsonContent(s.schemaOf(s.errorResponse)),
+		}
+	}
+
+	return responses
+}
+
+// jsonContent 生成 application/json 的 Content Object.
+func jsonContent(schema Schema) map[string]interface{} {
+	return map[string]interface{}{
+		"application/json": map[string]interface{}{"schema": schema},
+	}
+}
+
+// Check 对比已注册的路由和接口声明，返回路径以 prefix 开头但没有声明的路由，以及没有对应路由的声明.
+func (s *Spec) Check(routes []*echo.Route, prefix string) []string {
+	documented := make(map[string]bool, len(s.operations))
+	for _, op := range s.operations {
+		documented[op.Method+" "+op.Path] = false
+	}
+
+	var problems []string
+
+	for _, route := range routes {
+		// 路由组中间件注册的兜底路由不是接口
+		if !strings.HasPrefix(route.Path, prefix) || route.Method == echo.RouteNotFound {
+			continue
+		}
+
+		key := route.Method + " " + route.Path
+		if _, ok := documented[key]; !ok {
+			problems = append(problems, "路由缺少文档: "+key)
+			continue
+		}
+
+		documented[key] = true
+	}
+
+	for key, found := range documented {
+		if !found {
+			problems = append(problems, "文档没有对应的路由: "+key)
+		}
+	}
+
+	sort.Strings(problems)
+
+	return problems
+}
@@ -0,0 +1,172 @@
+package openapi
+
+import (
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Schema JSON Schema（OpenAPI 3.1 使用 JSON Schema 2020-12）.
+type Schema map[string]interface{}
+
+// timeType time.Time 序列化为 RFC 3339 字符串.
+var timeType = reflect.TypeOf(time.Time{})
+
+// Enum 由枚举类型实现，返回所有可选值，文档中生成 enum 约束.
+type Enum interface {
+	EnumValues() []string
+}
+
+// enumType Enum 接口类型.
+var enumType = reflect.TypeOf((*Enum)(nil)).Elem()
+
+// schemaOf 返回类型的 schema. 具名结构体和枚举类型放入 components.schemas 并返回引用.
+func (s *Spec) schemaOf(t reflect.Type) Schema {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	if t == timeType {
+		return Schema{"type": "string", "format": "date-time"}
+	}
+
+	if t.Implements(enumType) && t.Kind() == reflect.String {
+		return s.ref(t)
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return Schema{"type": "string"}
+	case reflect.Bool:
+		return Schema{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Schema{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return Schema{"type": "number"}
+	case reflect.Slice, reflect.Array:
+		return Schema{"type": "array", "items": s.schemaOf(t.Elem())}
+	case reflect.Map:
+		return Schema{"type": "object", "additionalProperties": s.schemaOf(t.Elem())}
+	case reflect.Struct:
+		if t.Name() == "" {
+			schema, _ := s.structSchema(t)
+			return schema
+		}
+
+		return s.ref(t)
+	default:
+		// interface{} 等无法确定类型的字段不加约束
+		return Schema{}
+	}
+}
+
+// ref 注册具名结构体或枚举类型并返回引用.
+func (s *Spec) ref(t reflect.Type) Schema {
+	name := t.Name()
+
+	if _, ok := s.schemas[name]; !ok {
+		// 先占位，避免自引用的类型无限递归
+		s.schemas[name] = Schema{}
+		s.schemas[name], s.propertyOrder[name] = s.namedSchema(t)
+	}
+
+	return Schema{"$ref": "#/components/schemas/" + name}
+}
+
+// namedSchema 生成具名类型的 schema，结构体同时返回按字段定义顺序排列的属性名.
+func (s *Spec) namedSchema(t reflect.Type) (Schema, []string) {
+	if enum, ok := reflect.Zero(t).Interface().(Enum); ok {
+		return Schema{"type": "string", "enum": enum.EnumValues()}, nil
+	}
+
+	return s.structSchema(t)
+}
+
+// structSchema 根据 json 和 validate 标签生成对象 schema.
+//
+// 字段在 validate 标签包含 required，或没有 validate 标签且 json 标签没有 omitempty 时视为必需；
+// validate 中的 min/max 转换为字符串长度限制，email 转换为 format.
+func (s *Spec) structSchema(t reflect.Type) (Schema, []string) {
+	properties := make(map[string]interface{})
+	order := []string{}
+	required := []string{}
+
+	s.collectFields(t, properties, &order, &required)
+
+	schema := Schema{"type": "object", "properties": properties}
+	if len(required) > 0 {
+		schema["required"] = required
+	}
+
+	return schema, order
+}
+
+// collectFields 收集结构体字段，匿名嵌入的结构体字段展开到外层.
+func (s *Spec) collectFields(t reflect.Type, properties map[string]interface{}, order, required *[]string) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+
+		if field.Anonymous && field.Type.Kind() == reflect.Struct && field.Tag.Get("json") == "" {
+			s.collectFields(field.Type, properties, order, required)
+			continue
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+
+		name, options, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			continue
+		}
+
+		if name == "" {
+			name = field.Name
+		}
+
+		schema := s.schemaOf(field.Type)
+
+		validate, hasValidate := field.Tag.Lookup("validate")
+		rules := strings.Split(validate, ",")
+
+		if _, isRef := schema["$ref"]; !isRef {
+			applyRules(schema, rules)
+		}
+
+		properties[name] = schema
+		*order = append(*order, name)
+
+		if hasRule(rules, "required") || (!hasValidate && !strings.Contains(options, "omitempty")) {
+			*required = append(*required, name)
+		}
+	}
+}
+
+// applyRules 将 validate 规则转换为 schema 约束.
+func applyRules(schema Schema, rules []string) {
+	for _, rule := range rules {
+		name, param, _ := strings.Cut(rule, "=")
+
+		switch {
+		case name == "email":
+			schema["format"] = "email"
+		case name == "min" && schema["type"] == "string":
+			schema["minLength"], _ = strconv.Atoi(param) //nolint:errcheck
+		case name == "max" && schema["type"] == "string":
+			schema["maxLength"], _ = strconv.Atoi(param) //nolint:errcheck
+		}
+	}
+}
+
+// hasRule 判断是否包含指定规则.
+func hasRule(rules []string, name string) bool {
+	for _, rule := range rules {
+		if rule == name {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,315 @@
+package openapi
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// TypeScript 生成的文件名.
+const (
+	TypesFile  = "types.ts"
+	ClientFile = "client.ts"
+)
+
+// generatedHeader 生成文件的头部注释.
+const generatedHeader = "// 由 go run ./cmd/tsgen 生成，请勿手动修改\n"
+
+// identifier 匹配可以不加引号的 TypeScript 属性名.
+var identifier = regexp.MustCompile(`^[A-Za-z_$][A-Za-z0-9_$]*$`)
+
+// TypeScript 生成前端使用的类型定义和 API 客户端，返回文件名到内容的映射.
+//
+// types.ts 包含 components.schemas 中的所有类型；client.ts 按 Tag 分组生成 xxxApi 对象，
+// 函数名为 OperationID，使用 web/src/lib/client 中的 axios 实例发送请求.
+func (s *Spec) TypeScript() map[string][]byte {
+	// 生成文档以填充 components.schemas
+	s.Document()
+
+	return map[string][]byte{
+		TypesFile:  []byte(s.typesFile()),
+		ClientFile: []byte(s.clientFile()),
+	}
+}
+
+// typesFile 生成类型定义文件，类型按名称排序.
+func (s *Spec) typesFile() string {
+	names := make([]string, 0, len(s.schemas))
+	for name := range s.schemas {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	var b strings.Builder
+	b.WriteString(generatedHeader)
+
+	for _, name := range names {
+		schema := s.schemas[name]
+		b.WriteString("\n")
+
+		if schema["type"] != "object" {
+			fmt.Fprintf(&b, "export type %s = %s;\n", name, tsType(schema))
+			continue
+		}
+
+		properties, _ := schema["properties"].(map[string]interface{})
+		required, _ := schema["required"].([]string)
+
+		fmt.Fprintf(&b, "export interface %s {\n", name)
+
+		for _, prop := range s.propertyOrder[name] {
+			fmt.Fprintf(&b, "  %s%s: %s;\n", propertyName(prop), optional(prop, required), tsType(asSchema(properties[prop])))
+		}
+
+		b.WriteString("}\n")
+	}
+
+	return b.String()
+}
+
+// clientFile 生成 API 客户端文件，分组按 Tag 首次出现的顺序排列.
+func (s *Spec) clientFile() string {
+	var (
+		tags   []string
+		groups = make(map[string][]string)
+		used   = make(map[string]bool)
+	)
+
+	for _, op := range s.operations {
+		tag := op.Tag
+		if tag == "" {
+			tag = "default"
+		}
+
+		if _, ok := groups[tag]; !ok {
+			tags = append(tags, tag)
+		}
+
+		groups[tag] = append(groups[tag], s.clientFunction(op, used))
+	}
+
+	var b strings.Builder
+	b.WriteString(generatedHeader)
+	b.WriteString("import client from \"../../lib/client\";\n")
+	b.WriteString("import type { ApiResponse } from \"../../lib/client\";\n")
+
+	if len(used) > 0 {
+		names := make([]string, 0, len(used))
+		for name := range used {
+			names = append(names, name)
+		}
+
+		sort.Strings(names)
+		fmt.Fprintf(&b, "import type { %s } from \"./types\";\n", strings.Join(names, ", "))
+	}
+
+	for _, tag := range tags {
+		fmt.Fprintf(&b, "\nexport const %sApi = {\n", tag)
+		b.WriteString(strings.Join(groups[tag], "\n"))
+		b.WriteString("};\n")
+	}
+
+	return b.String()
+}
+
+// clientFunction 生成单个接口的客户端函数，used 记录引用到的类型名.
+func (s *Spec) clientFunction(op Operation, used map[string]bool) string {
+	var (
+		params []string
+		args   []string
+	)
+
+	path := "\"" + op.Path + "\""
+	if matches := pathParam.FindAllStringSubmatch(op.Path, -1); len(matches) > 0 {
+		path = "`" + pathParam.ReplaceAllStringFunc(op.Path, func(m string) string {
+			return "${encodeURIComponent(" + m[1:] + ")}"
+		}) + "`"
+
+		for _, m := range matches {
+			params = append(params, m[1]+": string")
+		}
+	}
+
+	args = append(args, path)
+
+	if op.Request != nil {
+		request := s.typeName(op.Request, used)
+		params = append(params, "data: "+request)
+
+		switch op.Method {
+		case http.MethodPost, http.MethodPut, http.MethodPatch:
+			args = append(args, "data")
+		default:
+			args = append(args, "{ data }")
+		}
+	}
+
+	data := "null"
+	if op.Response != nil {
+		data = s.typeName(op.Response, used)
+	}
+
+	result := "ApiResponse<" + data + ">"
+	if op.Raw {
+		result = data
+	}
+
+	var b strings.Builder
+	if op.Deprecated {
+		fmt.Fprintf(&b, "  /** @deprecated %s */\n", op.Summary)
+	} else {
+		fmt.Fprintf(&b, "  /** %s */\n", op.Summary)
+	}
+	fmt.Fprintf(&b, "  %s: async (%s): Promise<%s> => {\n", op.OperationID, strings.Join(params, ", "), result)
+	fmt.Fprintf(&b, "    const response = await client.%s<%s>(%s);\n", strings.ToLower(op.Method), result, strings.Join(args, ", "))
+	b.WriteString("    return response.data;\n")
+	b.WriteString("  },\n")
+
+	return b.String()
+}
+
+// typeName 返回 Go 类型对应的 TypeScript 类型，并记录引用的具名类型.
+func (s *Spec) typeName(v interface{}, used map[string]bool) string {
+	schema := s.schemaOf(reflect.TypeOf(v))
+	collectRefs(schema, used)
+
+	return tsType(schema)
+}
+
+// collectRefs 收集 schema 中引用的具名类型.
+func collectRefs(value interface{}, used map[string]bool) {
+	switch v := value.(type) {
+	case Schema:
+		collectRefs(map[string]interface{}(v), used)
+	case map[string]interface{}:
+		if ref, ok := v["$ref"].(string); ok {
+			used[refName(ref)] = true
+		}
+
+		for _, child := range v {
+			collectRefs(child, used)
+		}
+	}
+}
+
+// tsType 将 schema 转换为 TypeScript 类型表达式.
+func tsType(schema Schema) string {
+	if ref, ok := schema["$ref"].(string); ok {
+		return refName(ref)
+	}
+
+	if value, ok := schema["const"]; ok {
+		return literal(value)
+	}
+
+	if enum, ok := schema["enum"].([]string); ok {
+		values := make([]string, len(enum))
+		for i, v := range enum {
+			values[i] = literal(v)
+		}
+
+		return strings.Join(values, " | ")
+	}
+
+	switch schema["type"] {
+	case "string":
+		return "string"
+	case "integer", "number":
+		return "number"
+	case "boolean":
+		return "boolean"
+	case "null":
+		return "null"
+	case "array":
+		item := tsType(asSchema(schema["items"]))
+		if strings.Contains(item, " ") {
+			item = "(" + item + ")"
+		}
+
+		return item + "[]"
+	case "object":
+		if additional, ok := schema["additionalProperties"]; ok {
+			return "Record<string, " + tsType(asSchema(additional)) + ">"
+		}
+
+		return inlineObject(schema)
+	default:
+		return "unknown"
+	}
+}
+
+// inlineObject 生成匿名结构体的内联对象类型，属性按名称排序.
+func inlineObject(schema Schema) string {
+	properties, _ := schema["properties"].(map[string]interface{})
+	required, _ := schema["required"].([]string)
+
+	if len(properties) == 0 {
+		return "Record<string, never>"
+	}
+
+	names := make([]string, 0, len(properties))
+	for name := range properties {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+
+	fields := make([]string, len(names))
+	for i, name := range names {
+		fields[i] = propertyName(name) + optional(name, required) + ": " + tsType(asSchema(properties[name]))
+	}
+
+	return "{ " + strings.Join(fields, "; ") + " }"
+}
+
+// asSchema 将 properties、items 中的值转换为 Schema.
+func asSchema(value interface{}) Schema {
+	switch v := value.(type) {
+	case Schema:
+		return v
+	case map[string]interface{}:
+		return v
+	default:
+		return Schema{}
+	}
+}
+
+// refName 返回引用的类型名.
+func refName(ref string) string {
+	return ref[strings.LastIndex(ref, "/")+1:]
+}
+
+// propertyName 返回属性名，不是合法标识符时加引号.
+func propertyName(name string) string {
+	if identifier.MatchString(name) {
+		return name
+	}
+
+	return literal(name)
+}
+
+// optional 非必需属性返回 "?".
+func optional(name string, required []string) string {
+	for _, r := range required {
+		if r == name {
+			return ""
+		}
+	}
+
+	return "?"
+}
+
+// literal 生成 JSON 字面量，字符串使用双引号.
+func literal(value interface{}) string {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "unknown"
+	}
+
+	return string(data)
+}
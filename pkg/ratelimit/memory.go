@@ -0,0 +1,93 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval 清理已补满的令牌桶的最小间隔.
+const sweepInterval = time.Minute
+
+// bucket 令牌桶. 只记录"令牌桶补满的时间"：当前令牌数可以由它和速率推算出来.
+type bucket struct {
+	full time.Time
+}
+
+// MemoryStore 进程内的令牌桶存储，多个实例之间不共享状态.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore 创建内存存储.
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets: make(map[string]*bucket),
+		now:     time.Now,
+	}
+}
+
+// Take 从 key 对应的令牌桶中取出一个令牌.
+//
+// 令牌桶以"补满时间"表示：full 之前每提前 interval 少一个令牌. 取令牌即把 full 推后一个 interval，
+// 推后之后超过一个周期说明桶已空，拒绝请求.
+func (s *MemoryStore) Take(_ context.Context, key string, limit Limit) (Result, error) {
+	if limit.IsZero() {
+		return Result{Allowed: true}, nil
+	}
+
+	now := s.now()
+	interval := limit.interval()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{full: now}
+		s.buckets[key] = b
+	}
+
+	full := b.full
+	if full.Before(now) {
+		full = now
+	}
+
+	next := full.Add(interval)
+	if next.Sub(now) > limit.Period {
+		return Result{
+			Allowed:    false,
+			Remaining:  0,
+			Reset:      full.Sub(now),
+			RetryAfter: next.Sub(now) - limit.Period,
+		}, nil
+	}
+
+	b.full = next
+
+	return Result{
+		Allowed:   true,
+		Remaining: int((limit.Period - next.Sub(now)) / interval),
+		Reset:     next.Sub(now),
+	}, nil
+}
+
+// sweep 删除已经补满的令牌桶，避免长期运行时内存增长. 调用方需持有锁.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+
+	s.lastSweep = now
+
+	for key, b := range s.buckets {
+		if !b.full.After(now) {
+			delete(s.buckets, key)
+		}
+	}
+}
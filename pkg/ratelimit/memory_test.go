@@ -0,0 +1,117 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+// take 一次取令牌操作：先将时钟前进 advance，再从 key 对应的令牌桶中取令牌.
+type take struct {
+	advance time.Duration
+	key     string
+	want    Result
+}
+
+func TestMemoryStoreTake(t *testing.T) {
+	// 3/3s 每秒恢复一个令牌
+	limit := Limit{Requests: 3, Period: 3 * time.Second}
+
+	tests := []struct {
+		name  string
+		limit Limit
+		takes []take
+	}{
+		{
+			name:  "突发用完整个周期的配额",
+			limit: limit,
+			takes: []take{
+				{want: Result{Allowed: true, Remaining: 2, Reset: time.Second}},
+				{want: Result{Allowed: true, Remaining: 1, Reset: 2 * time.Second}},
+				{want: Result{Allowed: true, Remaining: 0, Reset: 3 * time.Second}},
+				{want: Result{Allowed: false, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second}},
+			},
+		},
+		{
+			name:  "按平均速率恢复",
+			limit: limit,
+			takes: []take{
+				{},
+				{},
+				{},
+				{advance: time.Second, want: Result{Allowed: true, Remaining: 0, Reset: 3 * time.Second}},
+				{want: Result{Allowed: false, Remaining: 0, Reset: 3 * time.Second, RetryAfter: time.Second}},
+			},
+		},
+		{
+			name:  "不足一个令牌时拒绝",
+			limit: limit,
+			takes: []take{
+				{},
+				{},
+				{},
+				{advance: 500 * time.Millisecond, want: Result{Allowed: false, Remaining: 0, Reset: 2500 * time.Millisecond, RetryAfter: 500 * time.Millisecond}},
+				{advance: 500 * time.Millisecond, want: Result{Allowed: true, Remaining: 0, Reset: 3 * time.Second}},
+			},
+		},
+		{
+			name:  "补满后恢复全部配额",
+			limit: limit,
+			takes: []take{
+				{},
+				{},
+				{},
+				{advance: 10 * time.Second, want: Result{Allowed: true, Remaining: 2, Reset: time.Second}},
+			},
+		},
+		{
+			name:  "不同的键互不影响",
+			limit: Limit{Requests: 1, Period: time.Minute},
+			takes: []take{
+				{key: "a", want: Result{Allowed: true, Remaining: 0, Reset: time.Minute}},
+				{key: "a", want: Result{Allowed: false, Remaining: 0, Reset: time.Minute, RetryAfter: time.Minute}},
+				{key: "b", want: Result{Allowed: true, Remaining: 0, Reset: time.Minute}},
+			},
+		},
+		{
+			name:  "零值不限流",
+			limit: Limit{},
+			takes: []take{
+				{want: Result{Allowed: true}},
+				{want: Result{Allowed: true}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+			store := NewMemoryStore()
+			store.now = func() time.Time { return now }
+
+			for i, step := range tt.takes {
+				now = now.Add(step.advance)
+
+				key := step.key
+				if key == "" {
+					key = "key"
+				}
+
+				got, err := store.Take(context.Background(), key, tt.limit)
+				if err != nil {
+					t.Fatalf("第 %d 次 Take 失败: %v", i+1, err)
+				}
+
+				// 只用于消耗令牌的步骤不检查结果
+				if step.want == (Result{}) {
+					continue
+				}
+
+				if got != step.want {
+					t.Fatalf("第 %d 次 Take = %+v, want %+v", i+1, got, step.want)
+				}
+			}
+		})
+	}
+}
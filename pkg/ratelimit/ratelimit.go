@@ -0,0 +1,92 @@
+// Package ratelimit 令牌桶限流，提供单实例使用的内存存储，多实例部署时可以实现 Store 接口共享限流状态
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Limit 限流速率：每个 Period 最多 Requests 个请求. 令牌桶容量为 Requests，按 Requests/Period 的速率补充，
+// 因此允许一次性用完整个周期的配额，之后按平均速率放行.
+type Limit struct {
+	Requests int
+	Period   time.Duration
+}
+
+// ParseLimit 解析 "<请求数>/<周期>" 格式的限流速率，例如 10/1m、100/1h；周期为 1 时可以省略数字，如 5/s.
+// 空字符串返回零值，表示不限流.
+func ParseLimit(s string) (Limit, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Limit{}, nil
+	}
+
+	count, period, ok := strings.Cut(s, "/")
+	if !ok {
+		return Limit{}, fmt.Errorf("限流速率 %q 格式应为 <请求数>/<周期>，例如 10/1m", s)
+	}
+
+	requests, err := strconv.Atoi(strings.TrimSpace(count))
+	if err != nil || requests <= 0 {
+		return Limit{}, fmt.Errorf("限流速率 %q 的请求数应为正整数", s)
+	}
+
+	period = strings.TrimSpace(period)
+	if period != "" && (period[0] < '0' || period[0] > '9') {
+		period = "1" + period
+	}
+
+	d, err := time.ParseDuration(period)
+	if err != nil || d <= 0 {
+		return Limit{}, fmt.Errorf("限流速率 %q 的周期无效", s)
+	}
+
+	return Limit{Requests: requests, Period: d}, nil
+}
+
+// IsZero 判断是否为不限流的零值.
+func (l Limit) IsZero() bool {
+	return l.Requests <= 0 || l.Period <= 0
+}
+
+// String 返回 "<请求数>/<周期>" 格式.
+func (l Limit) String() string {
+	return strconv.Itoa(l.Requests) + "/" + l.Period.String()
+}
+
+// interval 返回补充一个令牌所需的时间.
+func (l Limit) interval() time.Duration {
+	return l.Period / time.Duration(l.Requests)
+}
+
+// Result 一次取令牌的结果.
+type Result struct {
+	Allowed    bool
+	Remaining  int           // 桶中剩余的令牌数
+	Reset      time.Duration // 令牌桶补满所需的时间
+	RetryAfter time.Duration // 被拒绝时距离下一个可用令牌的时间
+}
+
+// Store 令牌桶状态存储.
+type Store interface {
+	// Take 从 key 对应的令牌桶中取出一个令牌，key 不存在时视为满桶.
+	Take(ctx context.Context, key string, limit Limit) (Result, error)
+}
+
+// ErrUnknownBackend 不支持的存储后端.
+var ErrUnknownBackend = errors.New("不支持的限流存储后端")
+
+// NewStore 根据配置的后端名称创建存储. 目前只内置 memory；多实例部署需要共享限流状态时，
+// 实现 Store 接口（例如基于 Redis）并在这里注册.
+func NewStore(backend string) (Store, error) {
+	switch backend {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnknownBackend, backend)
+	}
+}
@@ -0,0 +1,103 @@
+package repo
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mattn/go-sqlite3"
+)
+
+// ErrNotFound 记录不存在.
+var ErrNotFound = errors.New("记录不存在")
+
+// DuplicateError 唯一约束冲突，Field 为冲突的列名（例如 email、username）.
+type DuplicateError struct {
+	Field string
+	Err   error
+}
+
+func (e *DuplicateError) Error() string {
+	return fmt.Sprintf("字段 %s 违反唯一约束: %v", e.Field, e.Err)
+}
+
+func (e *DuplicateError) Unwrap() error {
+	return e.Err
+}
+
+// IsDuplicate 判断错误是否为指定字段的唯一约束冲突.
+func IsDuplicate(err error, field string) bool {
+	var dup *DuplicateError
+	return errors.As(err, &dup) && dup.Field == field
+}
+
+// mysqlDuplicateKey 从 MySQL 错误信息中提取索引名，例如 Duplicate entry 'x' for key 'users.idx_users_email'.
+var mysqlDuplicateKey = regexp.MustCompile(`for key '(?:[^'.]+\.)?([^']+)'`)
+
+// translateError 将驱动相关的唯一约束错误转换为 DuplicateError，其他错误原样返回.
+func translateError(err error) error {
+	if field, ok := duplicateField(err); ok {
+		return &DuplicateError{Field: field, Err: err}
+	}
+
+	return err
+}
+
+// duplicateField 识别各数据库驱动的唯一约束冲突，并返回冲突的列名.
+func duplicateField(err error) (string, bool) {
+	var (
+		pgErr     *pgconn.PgError
+		mysqlErr  *mysql.MySQLError
+		sqliteErr sqlite3.Error
+	)
+
+	switch {
+	case errors.As(err, &pgErr) && pgErr.Code == "23505":
+		return columnFromIndex(pgErr.ConstraintName), true
+	case errors.As(err, &mysqlErr) && mysqlErr.Number == 1062:
+		if m := mysqlDuplicateKey.FindStringSubmatch(mysqlErr.Message); m != nil {
+			return columnFromIndex(m[1]), true
+		}
+
+		return "", true
+	case errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique:
+		// UNIQUE constraint failed: users.email
+		_, column, _ := strings.Cut(sqliteErr.Error(), ".")
+		return column, true
+	default:
+		return "", false
+	}
+}
+
+// columnFromIndex 从索引名推导列名，兼容 GORM 的 idx_users_email 和 PostgreSQL 默认的 users_email_key.
+func columnFromIndex(name string) string {
+	name = strings.TrimPrefix(name, "idx_")
+	name = strings.TrimSuffix(name, "_key")
+
+	return strings.TrimPrefix(name, "users_")
+}
+
+// isRetryable 判断错误是否为可以通过重试事务解决的序列化冲突或死锁.
+func isRetryable(err error) bool {
+	var (
+		pgErr     *pgconn.PgError
+		mysqlErr  *mysql.MySQLError
+		sqliteErr sqlite3.Error
+	)
+
+	switch {
+	case errors.As(err, &pgErr):
+		// serialization_failure, deadlock_detected
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	case errors.As(err, &mysqlErr):
+		// ER_LOCK_DEADLOCK, ER_LOCK_WAIT_TIMEOUT
+		return mysqlErr.Number == 1213 || mysqlErr.Number == 1205
+	case errors.As(err, &sqliteErr):
+		return sqliteErr.Code == sqlite3.ErrBusy || sqliteErr.Code == sqlite3.ErrLocked
+	default:
+		return false
+	}
+}
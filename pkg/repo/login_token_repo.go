@@ -0,0 +1,167 @@
+package repo
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"go-react-template/pkg/database"
+	"go-react-template/pkg/model"
+
+	"gorm.io/gorm"
+	"gorm.io/plugin/dbresolver"
+)
+
+// LoginTokenRepo 持久登录令牌数据访问接口.
+type LoginTokenRepo interface {
+	Create(ctx context.Context, token *model.LoginToken) error
+	GetByID(ctx context.Context, id string) (*model.LoginToken, error)
+	Rotate(ctx context.Context, token *model.LoginToken, oldHash string) (bool, error)
+	ListByUser(ctx context.Context, userID string) ([]model.LoginToken, error)
+	Delete(ctx context.Context, userID, id string) error
+	DeleteByUser(ctx context.Context, userID string) error
+	DeleteExpired(ctx context.Context, userID string, now time.Time) error
+}
+
+// loginTokenRepo 持久登录令牌数据访问实现.
+type loginTokenRepo struct {
+	db             *gorm.DB
+	queryTimeout   time.Duration
+	readYourWrites *database.ReadYourWrites
+}
+
+// NewLoginTokenRepo 创建持久登录令牌数据访问实例，参数与 NewUserRepo 一致.
+func NewLoginTokenRepo(db *gorm.DB, queryTimeout time.Duration, readYourWrites *database.ReadYourWrites) LoginTokenRepo {
+	return &loginTokenRepo{
+		db:             db,
+		queryTimeout:   queryTimeout,
+		readYourWrites: readYourWrites,
+	}
+}
+
+// conn 返回绑定了请求context和超时的数据库会话.
+func (r *loginTokenRepo) conn(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return r.db.WithContext(ctx), func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+
+	return r.db.WithContext(ctx), cancel
+}
+
+// Create 创建令牌系列.
+func (r *loginTokenRepo) Create(ctx context.Context, token *model.LoginToken) error {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	if err := db.Create(token).Error; err != nil {
+		return err
+	}
+
+	r.readYourWrites.MarkWrite(ctx)
+
+	return nil
+}
+
+// GetByID 根据系列ID获取令牌. 总是从主库读取：副本延迟会让刚轮换的令牌被误判为重复使用.
+func (r *loginTokenRepo) GetByID(ctx context.Context, id string) (*model.LoginToken, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	var token model.LoginToken
+
+	if err := db.Clauses(dbresolver.Write).Where("id = ?", id).First(&token).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotFound
+		}
+
+		return nil, err
+	}
+
+	return &token, nil
+}
+
+// Rotate 仅当数据库中的令牌哈希仍为 oldHash 时，写入 token 中的新哈希、使用时间和客户端信息，
+// 返回是否更新成功. 并发请求使用同一个令牌时只有一个能轮换成功.
+func (r *loginTokenRepo) Rotate(ctx context.Context, token *model.LoginToken, oldHash string) (bool, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	result := db.Model(&model.LoginToken{}).
+		Where("id = ? AND token_hash = ?", token.ID, oldHash).
+		Updates(map[string]interface{}{
+			"token_hash":    token.TokenHash,
+			"previous_hash": token.PreviousHash,
+			"user_agent":    token.UserAgent,
+			"ip":            token.IP,
+			"last_used_at":  token.LastUsedAt,
+			"expires_at":    token.ExpiresAt,
+		})
+	if result.Error != nil {
+		return false, result.Error
+	}
+
+	r.readYourWrites.MarkWrite(ctx)
+
+	return result.RowsAffected == 1, nil
+}
+
+// ListByUser 获取用户所有未过期的令牌，最近使用的在前.
+func (r *loginTokenRepo) ListByUser(ctx context.Context, userID string) ([]model.LoginToken, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	var tokens []model.LoginToken
+
+	err := r.readYourWrites.Reader(ctx, db).
+		Where("user_id = ? AND expires_at > ?", userID, time.Now()).
+		Order("last_used_at DESC").
+		Find(&tokens).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return tokens, nil
+}
+
+// Delete 删除用户的指定令牌系列，不存在时返回 ErrNotFound.
+func (r *loginTokenRepo) Delete(ctx context.Context, userID, id string) error {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	result := db.Where("id = ? AND user_id = ?", id, userID).Delete(&model.LoginToken{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	r.readYourWrites.MarkWrite(ctx)
+
+	if result.RowsAffected == 0 {
+		return ErrNotFound
+	}
+
+	return nil
+}
+
+// DeleteByUser 删除用户的所有令牌.
+func (r *loginTokenRepo) DeleteByUser(ctx context.Context, userID string) error {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	if err := db.Where("user_id = ?", userID).Delete(&model.LoginToken{}).Error; err != nil {
+		return err
+	}
+
+	r.readYourWrites.MarkWrite(ctx)
+
+	return nil
+}
+
+// DeleteExpired 删除用户已过期的令牌.
+func (r *loginTokenRepo) DeleteExpired(ctx context.Context, userID string, now time.Time) error {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	return db.Where("user_id = ? AND expires_at <= ?", userID, now).Delete(&model.LoginToken{}).Error
+}
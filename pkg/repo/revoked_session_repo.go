@@ -0,0 +1,80 @@
+package repo
+
+import (
+	"context"
+	"time"
+
+	"go-react-template/pkg/model"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/plugin/dbresolver"
+)
+
+// RevokedSessionRepo 已注销会话数据访问接口.
+type RevokedSessionRepo interface {
+	Create(ctx context.Context, session *model.RevokedSession) error
+	Exists(ctx context.Context, sessionID string, now time.Time) (bool, error)
+	DeleteExpired(ctx context.Context, now time.Time) error
+}
+
+// revokedSessionRepo 已注销会话数据访问实现.
+type revokedSessionRepo struct {
+	db           *gorm.DB
+	queryTimeout time.Duration
+}
+
+// NewRevokedSessionRepo 创建已注销会话数据访问实例，queryTimeout 为单次数据库操作的超时时间，0 表示不限制.
+func NewRevokedSessionRepo(db *gorm.DB, queryTimeout time.Duration) RevokedSessionRepo {
+	return &revokedSessionRepo{
+		db:           db,
+		queryTimeout: queryTimeout,
+	}
+}
+
+// conn 返回绑定了请求context和超时的数据库会话.
+func (r *revokedSessionRepo) conn(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return r.db.WithContext(ctx), func() {}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
+
+	return r.db.WithContext(ctx), cancel
+}
+
+// Create 记录会话已注销，已有记录时不做任何事.
+func (r *revokedSessionRepo) Create(ctx context.Context, session *model.RevokedSession) error {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(session).Error
+}
+
+// Exists 判断会话是否已注销且记录未过期. 总是从主库读取：注销后其他客户端可能立即使用旧Cookie，
+// 副本延迟会让刚注销的会话继续有效.
+func (r *revokedSessionRepo) Exists(ctx context.Context, sessionID string, now time.Time) (bool, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	var count int64
+
+	err := db.Clauses(dbresolver.Write).
+		Model(&model.RevokedSession{}).
+		Where("session_id = ? AND expires_at > ?", sessionID, now).
+		Limit(1).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// DeleteExpired 删除已过期的记录.
+func (r *revokedSessionRepo) DeleteExpired(ctx context.Context, now time.Time) error {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
+	return db.Where("expires_at <= ?", now).Delete(&model.RevokedSession{}).Error
+}
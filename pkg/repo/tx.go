@@ -0,0 +1,96 @@
+package repo
+
+import (
+	"context"
+	"math/rand/v2"
+	"time"
+
+	"go-react-template/pkg/database"
+
+	"gorm.io/gorm"
+)
+
+// maxTxAttempts 事务遇到序列化冲突或死锁时的最大尝试次数.
+const maxTxAttempts = 3
+
+// Repos 绑定到同一个数据库会话（通常是同一个事务）的一组仓储.
+type Repos struct {
+	Users       UserRepo
+	LoginTokens LoginTokenRepo
+}
+
+// TxFunc 在事务中执行的函数，ctx 携带事务，repos 中的仓储均绑定到该事务.
+type TxFunc func(ctx context.Context, repos Repos) error
+
+// TxManager 事务管理器.
+type TxManager interface {
+	// WithinTx 在事务中执行 fn：fn 返回错误时回滚，否则提交；
+	// 遇到序列化冲突或死锁时整体重试. 在事务中嵌套调用时使用保存点.
+	WithinTx(ctx context.Context, fn TxFunc) error
+}
+
+// WithPrimary 返回要求读请求都走主库的 context. 根据读到的数据轮换或吊销凭据时使用，避免副本复制延迟导致误判.
+func WithPrimary(ctx context.Context) context.Context {
+	return database.WithPrimary(ctx)
+}
+
+// txKey context中保存当前事务的键.
+type txKey struct{}
+
+// txManager 基于GORM的事务管理器实现.
+type txManager struct {
+	db             *gorm.DB
+	queryTimeout   time.Duration
+	readYourWrites *database.ReadYourWrites
+}
+
+// NewTxManager 创建事务管理器实例，参数与 NewUserRepo 一致. 事务始终在主库上执行.
+func NewTxManager(db *gorm.DB, queryTimeout time.Duration, readYourWrites *database.ReadYourWrites) TxManager {
+	return &txManager{
+		db:             db,
+		queryTimeout:   queryTimeout,
+		readYourWrites: readYourWrites,
+	}
+}
+
+// WithinTx 在事务中执行 fn.
+func (m *txManager) WithinTx(ctx context.Context, fn TxFunc) error {
+	// 已经在事务中，使用保存点实现嵌套事务，由外层负责重试
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx).Transaction(func(nested *gorm.DB) error {
+			return m.run(ctx, nested, fn)
+		})
+	}
+
+	var err error
+
+	for attempt := 1; attempt <= maxTxAttempts; attempt++ {
+		err = m.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+			return m.run(ctx, tx, fn)
+		})
+		if err == nil || !isRetryable(err) || attempt == maxTxAttempts {
+			break
+		}
+
+		// 随机退避，避免冲突的事务同时重试
+		backoff := time.Duration(attempt)*10*time.Millisecond + time.Duration(rand.N(10))*time.Millisecond //nolint:gosec // 退避抖动不需要安全随机数
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+	}
+
+	return err
+}
+
+// run 使用绑定到 tx 的仓储执行 fn.
+func (m *txManager) run(ctx context.Context, tx *gorm.DB, fn TxFunc) error {
+	ctx = context.WithValue(ctx, txKey{}, tx)
+
+	return fn(ctx, Repos{
+		Users:       NewUserRepo(tx, m.queryTimeout, m.readYourWrites),
+		LoginTokens: NewLoginTokenRepo(tx, m.queryTimeout, m.readYourWrites),
+	})
+}
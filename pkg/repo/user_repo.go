@@ -2,7 +2,9 @@
 package repo
 
 import (
+	"context"
 	"errors"
+	"time"
 
 	"go-react-template/pkg/database"
 	"go-react-template/pkg/model"
@@ -12,92 +14,101 @@ import (
 
 // UserRepo 用户数据访问接口.
 type UserRepo interface {
-	Create(user *model.User) error
-	Update(user *model.User) error
-	GetByEmail(email string) (*model.User, error)
-	GetByID(id string) (*model.User, error)
-	GetByUsername(username string) (*model.User, error)
-	GetByGoogleID(googleID string) (*model.User, error)
+	Create(ctx context.Context, user *model.User) error
+	Update(ctx context.Context, user *model.User) error
+	GetByEmail(ctx context.Context, email string) (*model.User, error)
+	GetByID(ctx context.Context, id string) (*model.User, error)
+	GetByUsername(ctx context.Context, username string) (*model.User, error)
+	GetByGoogleID(ctx context.Context, googleID string) (*model.User, error)
 }
 
 // userRepo 用户数据访问实现.
 type userRepo struct {
-	db *gorm.DB
+	db             *gorm.DB
+	queryTimeout   time.Duration
+	readYourWrites *database.ReadYourWrites
 }
 
-// NewUserRepo 创建用户数据访问实例.
-func NewUserRepo() UserRepo {
+// NewUserRepo 创建用户数据访问实例，queryTimeout 为单次数据库操作的超时时间，0 表示不限制；
+// readYourWrites 为 nil 时读请求始终按 dbresolver 的默认策略路由.
+func NewUserRepo(db *gorm.DB, queryTimeout time.Duration, readYourWrites *database.ReadYourWrites) UserRepo {
 	return &userRepo{
-		db: database.GetDB(),
+		db:             db,
+		queryTimeout:   queryTimeout,
+		readYourWrites: readYourWrites,
 	}
 }
 
-// Create 创建用户.
-func (r *userRepo) Create(user *model.User) error {
-	return r.db.Create(user).Error
-}
+// conn 返回绑定了请求context和超时的数据库会话.
+func (r *userRepo) conn(ctx context.Context) (*gorm.DB, context.CancelFunc) {
+	if r.queryTimeout <= 0 {
+		return r.db.WithContext(ctx), func() {}
+	}
 
-// Update 更新用户信息.
-func (r *userRepo) Update(user *model.User) error {
-	return r.db.Save(user).Error
-}
+	ctx, cancel := context.WithTimeout(ctx, r.queryTimeout)
 
-// GetByEmail 根据邮箱获取用户.
-func (r *userRepo) GetByEmail(email string) (*model.User, error) {
-	var user model.User
+	return r.db.WithContext(ctx), cancel
+}
 
-	err := r.db.Where("email = ?", email).First(&user).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("用户不存在")
-		}
+// Create 创建用户，违反唯一约束时返回 *DuplicateError.
+func (r *userRepo) Create(ctx context.Context, user *model.User) error {
+	db, cancel := r.conn(ctx)
+	defer cancel()
 
-		return nil, err
+	if err := db.Create(user).Error; err != nil {
+		return translateError(err)
 	}
 
-	return &user, nil
+	r.readYourWrites.MarkWrite(ctx)
+
+	return nil
 }
 
-// GetByID 根据ID获取用户.
-func (r *userRepo) GetByID(id string) (*model.User, error) {
-	var user model.User
+// Update 更新用户信息，违反唯一约束时返回 *DuplicateError.
+func (r *userRepo) Update(ctx context.Context, user *model.User) error {
+	db, cancel := r.conn(ctx)
+	defer cancel()
 
-	err := r.db.First(&user, id).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("用户不存在")
-		}
-
-		return nil, err
+	if err := db.Save(user).Error; err != nil {
+		return translateError(err)
 	}
 
-	return &user, nil
-}
+	r.readYourWrites.MarkWrite(ctx)
 
-// GetByUsername 根据用户名获取用户.
-func (r *userRepo) GetByUsername(username string) (*model.User, error) {
-	var user model.User
+	return nil
+}
 
-	err := r.db.Where("username = ?", username).First(&user).Error
-	if err != nil {
-		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("用户不存在")
-		}
+// GetByEmail 根据邮箱获取用户.
+func (r *userRepo) GetByEmail(ctx context.Context, email string) (*model.User, error) {
+	return r.first(ctx, "email = ?", email)
+}
 
-		return nil, err
-	}
+// GetByID 根据ID获取用户.
+func (r *userRepo) GetByID(ctx context.Context, id string) (*model.User, error) {
+	return r.first(ctx, "id = ?", id)
+}
 
-	return &user, nil
+// GetByUsername 根据用户名获取用户.
+func (r *userRepo) GetByUsername(ctx context.Context, username string) (*model.User, error) {
+	return r.first(ctx, "username = ?", username)
 }
 
 // GetByGoogleID 根据Google ID获取用户.
-func (r *userRepo) GetByGoogleID(googleID string) (*model.User, error) {
+func (r *userRepo) GetByGoogleID(ctx context.Context, googleID string) (*model.User, error) {
+	return r.first(ctx, "google_id = ?", googleID)
+}
+
+// first 按条件查询第一个用户，配置了只读副本时从副本读取（事务内和读写粘滞窗口内走主库）.
+func (r *userRepo) first(ctx context.Context, query string, args ...interface{}) (*model.User, error) {
+	db, cancel := r.conn(ctx)
+	defer cancel()
+
 	var user model.User
 
-	err := r.db.Where("google_id = ?", googleID).First(&user).Error
+	err := r.readYourWrites.Reader(ctx, db).Where(query, args...).First(&user).Error
 	if err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
-			return nil, errors.New("用户不存在")
+			return nil, ErrNotFound
 		}
 
 		return nil, err
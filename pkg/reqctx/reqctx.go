@@ -0,0 +1,71 @@
+// Package reqctx 在 context.Context 中携带请求级元数据（请求ID、用户ID、语言等），
+// 使日志、链路追踪等横切逻辑可以在 handler、service、repo 各层读取
+package reqctx
+
+import "context"
+
+// ctxKey context键类型，避免与其他包冲突.
+type ctxKey int
+
+const (
+	requestIDKey ctxKey = iota
+	userIDKey
+	localeKey
+	cspNonceKey
+	clientIPKey
+)
+
+// WithRequestID 返回携带请求ID的context.
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, requestIDKey, requestID)
+}
+
+// RequestID 从context中获取请求ID，不存在时返回空字符串.
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey).(string) //nolint:errcheck
+	return id
+}
+
+// WithUserID 返回携带当前用户ID的context.
+func WithUserID(ctx context.Context, userID string) context.Context {
+	return context.WithValue(ctx, userIDKey, userID)
+}
+
+// UserID 从context中获取当前用户ID，未登录时返回空字符串.
+func UserID(ctx context.Context) string {
+	id, _ := ctx.Value(userIDKey).(string) //nolint:errcheck
+	return id
+}
+
+// WithClientIP 返回携带客户端IP的context.
+func WithClientIP(ctx context.Context, ip string) context.Context {
+	return context.WithValue(ctx, clientIPKey, ip)
+}
+
+// ClientIP 从context中获取客户端IP，不存在时返回空字符串.
+func ClientIP(ctx context.Context) string {
+	ip, _ := ctx.Value(clientIPKey).(string) //nolint:errcheck
+	return ip
+}
+
+// WithLocale 返回携带请求语言的context.
+func WithLocale(ctx context.Context, locale string) context.Context {
+	return context.WithValue(ctx, localeKey, locale)
+}
+
+// Locale 从context中获取请求语言，未协商时返回空字符串.
+func Locale(ctx context.Context) string {
+	locale, _ := ctx.Value(localeKey).(string) //nolint:errcheck
+	return locale
+}
+
+// WithCSPNonce 返回携带内容安全策略 nonce 的context.
+func WithCSPNonce(ctx context.Context, nonce string) context.Context {
+	return context.WithValue(ctx, cspNonceKey, nonce)
+}
+
+// CSPNonce 从context中获取内容安全策略 nonce，未启用时返回空字符串.
+func CSPNonce(ctx context.Context) string {
+	nonce, _ := ctx.Value(cspNonceKey).(string) //nolint:errcheck
+	return nonce
+}
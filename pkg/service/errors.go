@@ -0,0 +1,32 @@
+package service
+
+import (
+	"go-react-template/pkg/apperr"
+	"go-react-template/pkg/repo"
+)
+
+// 业务错误，错误码对应消息目录中的 error.<错误码>.
+var (
+	ErrEmailTaken         = apperr.New(apperr.KindConflict, "email_taken")
+	ErrUsernameTaken      = apperr.New(apperr.KindConflict, "username_taken")
+	ErrUserNotFound       = apperr.New(apperr.KindNotFound, "user_not_found")
+	ErrEmailBoundToLocal  = apperr.New(apperr.KindConflict, "email_bound_to_local")
+	ErrInvalidCredentials = apperr.New(apperr.KindUnauthenticated, "invalid_credentials")
+	ErrAccountBanned      = apperr.New(apperr.KindForbidden, "account_banned")
+	ErrWrongPassword      = apperr.New(apperr.KindInvalid, "wrong_password")
+	ErrInvalidGoogleToken = apperr.New(apperr.KindUnauthenticated, "invalid_google_token")
+	ErrGoogleEmailMissing = apperr.New(apperr.KindUnauthenticated, "google_email_missing")
+	ErrLoginTokenNotFound = apperr.New(apperr.KindNotFound, "login_token_not_found")
+)
+
+// mapDuplicate 将仓储层的唯一约束冲突映射为业务错误，无法识别时返回 nil.
+func mapDuplicate(err error) error {
+	switch {
+	case repo.IsDuplicate(err, "email"):
+		return ErrEmailTaken
+	case repo.IsDuplicate(err, "username"):
+		return ErrUsernameTaken
+	default:
+		return nil
+	}
+}
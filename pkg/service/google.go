@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"go.opentelemetry.io/contrib/instrumentation/net/http/otelhttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/api/idtoken"
+	"google.golang.org/api/option"
+)
+
+// GoogleTokenValidator Google ID Token 校验器.
+type GoogleTokenValidator interface {
+	Validate(ctx context.Context, idToken, audience string) (*idtoken.Payload, error)
+}
+
+// googleTokenValidator 在校验外包一层 span，获取Google公钥的HTTP请求也会被追踪.
+type googleTokenValidator struct {
+	validator *idtoken.Validator
+	tracer    trace.Tracer
+}
+
+// NewGoogleTokenValidator 创建带链路追踪的 Google ID Token 校验器.
+func NewGoogleTokenValidator(ctx context.Context, tp trace.TracerProvider, propagator propagation.TextMapPropagator) (GoogleTokenValidator, error) {
+	client := &http.Client{
+		Transport: otelhttp.NewTransport(http.DefaultTransport,
+			otelhttp.WithTracerProvider(tp),
+			otelhttp.WithPropagators(propagator),
+		),
+	}
+
+	validator, err := idtoken.NewValidator(ctx, option.WithHTTPClient(client))
+	if err != nil {
+		return nil, fmt.Errorf("创建Google ID Token校验器失败: %w", err)
+	}
+
+	return &googleTokenValidator{
+		validator: validator,
+		tracer:    tp.Tracer("go-react-template/pkg/service"),
+	}, nil
+}
+
+// Validate 校验 ID Token.
+func (v *googleTokenValidator) Validate(ctx context.Context, idToken, audience string) (*idtoken.Payload, error) {
+	ctx, span := v.tracer.Start(ctx, "google.idtoken.Validate", trace.WithSpanKind(trace.SpanKindClient))
+	defer span.End()
+
+	payload, err := v.validator.Validate(ctx, idToken, audience)
+
+	return payload, recordError(span, err)
+}
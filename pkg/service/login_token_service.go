@@ -0,0 +1,266 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"time"
+	"unicode/utf8"
+
+	"go-react-template/pkg/apperr"
+	"go-react-template/pkg/model"
+	"go-react-template/pkg/repo"
+)
+
+// loginTokenSecretSize 持久登录令牌中随机部分的字节数.
+const loginTokenSecretSize = 32
+
+// rotationGrace 令牌轮换后仍接受上一个令牌的时间，避免页面同时发出的多个请求被误判为令牌被盗用.
+const rotationGrace = 10 * time.Second
+
+// maxUserAgentLength 保存的 User-Agent 最大字符数，与 model.LoginToken 的列宽一致.
+const maxUserAgentLength = 255
+
+// LoginTokenService "记住我"持久登录令牌业务逻辑接口.
+//
+// 令牌格式见 model.LoginTokenValue，数据库只保存随机串的哈希. 每次使用后换发新令牌；
+// 已被轮换掉的令牌再次出现说明令牌可能被盗用，整个系列会被吊销.
+type LoginTokenService interface {
+	Issue(ctx context.Context, userID string, client model.LoginClient) (string, error)
+	Authenticate(ctx context.Context, token string, client model.LoginClient) (*model.User, string, error)
+	Exists(ctx context.Context, id string) (bool, error)
+	List(ctx context.Context, userID, currentID string) ([]model.LoginTokenResponse, error)
+	Revoke(ctx context.Context, userID, id string) error
+}
+
+// loginTokenService 持久登录令牌业务逻辑实现.
+type loginTokenService struct {
+	tokens repo.LoginTokenRepo
+	users  repo.UserRepo
+	logger *slog.Logger
+	ttl    time.Duration
+}
+
+// NewLoginTokenService 创建持久登录令牌业务逻辑实例，ttl 为令牌有效期，每次轮换后重新计算.
+func NewLoginTokenService(tokens repo.LoginTokenRepo, users repo.UserRepo, logger *slog.Logger, ttl time.Duration) LoginTokenService {
+	return &loginTokenService{
+		tokens: tokens,
+		users:  users,
+		logger: logger,
+		ttl:    ttl,
+	}
+}
+
+// Issue 为用户创建新的令牌系列，返回令牌.
+func (s *loginTokenService) Issue(ctx context.Context, userID string, client model.LoginClient) (string, error) {
+	secret, hash, err := newLoginTokenSecret()
+	if err != nil {
+		return "", err
+	}
+
+	now := time.Now()
+
+	// 顺带清理该用户已过期的令牌，失败不影响签发
+	if err := s.tokens.DeleteExpired(ctx, userID, now); err != nil {
+		s.logger.WarnContext(ctx, "清理过期持久登录令牌失败", "error", err)
+	}
+
+	token := &model.LoginToken{
+		UserID:     userID,
+		TokenHash:  hash,
+		UserAgent:  truncate(client.UserAgent, maxUserAgentLength),
+		IP:         client.IP,
+		LastUsedAt: now,
+		ExpiresAt:  now.Add(s.ttl),
+	}
+
+	if err := s.tokens.Create(ctx, token); err != nil {
+		return "", fmt.Errorf("持久登录令牌创建失败: %w", err)
+	}
+
+	s.logger.InfoContext(ctx, "签发持久登录令牌", "login_user_id", userID, "series", token.ID)
+
+	return model.LoginTokenValue(token.ID, secret), nil
+}
+
+// Authenticate 校验令牌并返回对应用户和换发的新令牌. 令牌无效时返回 apperr.ErrUnauthenticated.
+//
+// 并发请求使用同一个令牌时，只有一个请求换发新令牌，其余请求返回空的新令牌，调用方应保留浏览器中的令牌.
+func (s *loginTokenService) Authenticate(ctx context.Context, token string, client model.LoginClient) (*model.User, string, error) {
+	id, secret, ok := model.ParseLoginToken(token)
+	if !ok {
+		return nil, "", apperr.ErrUnauthenticated
+	}
+
+	record, err := s.tokens.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, "", apperr.ErrUnauthenticated
+		}
+
+		return nil, "", err
+	}
+
+	now := time.Now()
+	if !now.Before(record.ExpiresAt) {
+		s.revoke(ctx, record, "expired")
+		return nil, "", apperr.ErrUnauthenticated
+	}
+
+	hash := hashLoginTokenSecret(secret)
+
+	switch {
+	case equalHash(hash, record.TokenHash):
+	case equalHash(hash, record.PreviousHash) && now.Sub(record.LastUsedAt) <= rotationGrace:
+		// 其他请求刚刚完成轮换，本次请求只恢复登录
+		user, err := s.activeUser(ctx, record)
+		return user, "", err
+	default:
+		// 已被轮换掉的令牌再次出现：令牌可能被盗用，吊销整个系列，持有新令牌的一方也需要重新登录
+		s.logger.WarnContext(ctx, "持久登录令牌被重复使用，可能已被盗用，吊销整个系列",
+			"login_user_id", record.UserID, "series", record.ID)
+		s.revoke(ctx, record, "reused")
+
+		return nil, "", apperr.ErrUnauthenticated
+	}
+
+	user, err := s.activeUser(ctx, record)
+	if err != nil {
+		return nil, "", err
+	}
+
+	newSecret, newHash, err := newLoginTokenSecret()
+	if err != nil {
+		return nil, "", err
+	}
+
+	oldHash := record.TokenHash
+	record.PreviousHash = oldHash
+	record.TokenHash = newHash
+	record.UserAgent = truncate(client.UserAgent, maxUserAgentLength)
+	record.IP = client.IP
+	record.LastUsedAt = now
+	record.ExpiresAt = now.Add(s.ttl)
+
+	rotated, err := s.tokens.Rotate(ctx, record, oldHash)
+	if err != nil {
+		return nil, "", fmt.Errorf("持久登录令牌轮换失败: %w", err)
+	}
+
+	if !rotated {
+		// 并发请求抢先完成了轮换
+		return user, "", nil
+	}
+
+	return user, model.LoginTokenValue(record.ID, newSecret), nil
+}
+
+// activeUser 返回令牌所属的用户，用户不存在或已被封禁时吊销令牌. 用户从主库读取：刚封禁的用户不能借副本延迟恢复登录.
+func (s *loginTokenService) activeUser(ctx context.Context, record *model.LoginToken) (*model.User, error) {
+	user, err := s.users.GetByID(repo.WithPrimary(ctx), record.UserID)
+	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			s.revoke(ctx, record, "user_not_found")
+			return nil, apperr.ErrUnauthenticated
+		}
+
+		return nil, err
+	}
+
+	if user.IsBanned {
+		s.logger.WarnContext(ctx, "封禁用户尝试使用持久登录令牌", "login_user_id", user.ID)
+		s.revoke(ctx, record, "banned")
+
+		return nil, apperr.ErrUnauthenticated
+	}
+
+	return user, nil
+}
+
+// revoke 删除令牌系列，失败时只记录日志.
+func (s *loginTokenService) revoke(ctx context.Context, record *model.LoginToken, reason string) {
+	if err := s.tokens.Delete(ctx, record.UserID, record.ID); err != nil && !errors.Is(err, repo.ErrNotFound) {
+		s.logger.ErrorContext(ctx, "吊销持久登录令牌失败", "series", record.ID, "reason", reason, "error", err)
+	}
+}
+
+// Exists 判断令牌系列是否仍然存在，被吊销（包括因重复使用被吊销）的系列不存在.
+func (s *loginTokenService) Exists(ctx context.Context, id string) (bool, error) {
+	if _, err := s.tokens.GetByID(ctx, id); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return false, nil
+		}
+
+		return false, err
+	}
+
+	return true, nil
+}
+
+// List 获取用户所有未过期的令牌，currentID 为当前浏览器持有的系列ID.
+func (s *loginTokenService) List(ctx context.Context, userID, currentID string) ([]model.LoginTokenResponse, error) {
+	tokens, err := s.tokens.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	responses := make([]model.LoginTokenResponse, len(tokens))
+	for i := range tokens {
+		responses[i] = tokens[i].ToResponse(tokens[i].ID == currentID)
+	}
+
+	return responses, nil
+}
+
+// Revoke 吊销用户的指定令牌系列.
+func (s *loginTokenService) Revoke(ctx context.Context, userID, id string) error {
+	if err := s.tokens.Delete(ctx, userID, id); err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return ErrLoginTokenNotFound
+		}
+
+		return err
+	}
+
+	s.logger.InfoContext(ctx, "吊销持久登录令牌", "series", id)
+
+	return nil
+}
+
+// newLoginTokenSecret 生成令牌随机串及其哈希.
+func newLoginTokenSecret() (secret, hash string, err error) {
+	b := make([]byte, loginTokenSecretSize)
+	if _, err := rand.Read(b); err != nil {
+		return "", "", err
+	}
+
+	secret = base64.RawURLEncoding.EncodeToString(b)
+
+	return secret, hashLoginTokenSecret(secret), nil
+}
+
+// hashLoginTokenSecret 计算令牌随机串的哈希. 随机串熵足够高，不需要加盐的慢哈希.
+func hashLoginTokenSecret(secret string) string {
+	sum := sha256.Sum256([]byte(secret))
+	return hex.EncodeToString(sum[:])
+}
+
+// equalHash 以固定时间比较两个哈希，空值不与任何值相等.
+func equalHash(a, b string) bool {
+	return a != "" && b != "" && subtle.ConstantTimeCompare([]byte(a), []byte(b)) == 1
+}
+
+// truncate 按字符截断字符串.
+func truncate(s string, n int) string {
+	if utf8.RuneCountInString(s) <= n {
+		return s
+	}
+
+	return string([]rune(s)[:n])
+}
@@ -0,0 +1,63 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"go-react-template/pkg/model"
+	"go-react-template/pkg/repo"
+)
+
+// SessionRevocationService 已注销会话的记录和查询.
+//
+// 会话数据保存在浏览器的Cookie中，注销或更换会话ID后旧Cookie在到期前仍能通过签名校验，
+// 因此服务端记录这些会话ID，认证时拒绝；记录保留到会话原本的最晚到期时间.
+type SessionRevocationService interface {
+	Revoke(ctx context.Context, sessionID, userID string, expiresAt time.Time) error
+	IsRevoked(ctx context.Context, sessionID string) (bool, error)
+}
+
+// sessionRevocationService 已注销会话业务逻辑实现.
+type sessionRevocationService struct {
+	sessions repo.RevokedSessionRepo
+	logger   *slog.Logger
+}
+
+// NewSessionRevocationService 创建已注销会话业务逻辑实例.
+func NewSessionRevocationService(sessions repo.RevokedSessionRepo, logger *slog.Logger) SessionRevocationService {
+	return &sessionRevocationService{
+		sessions: sessions,
+		logger:   logger,
+	}
+}
+
+// Revoke 记录会话已注销，expiresAt 为会话原本的最晚到期时间，已经到期的会话不需要记录.
+func (s *sessionRevocationService) Revoke(ctx context.Context, sessionID, userID string, expiresAt time.Time) error {
+	now := time.Now()
+	if !expiresAt.After(now) {
+		return nil
+	}
+
+	// 顺带清理已过期的记录，失败不影响注销
+	if err := s.sessions.DeleteExpired(ctx, now); err != nil {
+		s.logger.WarnContext(ctx, "清理过期的已注销会话失败", "error", err)
+	}
+
+	err := s.sessions.Create(ctx, &model.RevokedSession{
+		SessionID: sessionID,
+		UserID:    userID,
+		ExpiresAt: expiresAt,
+	})
+	if err != nil {
+		return fmt.Errorf("会话注销记录失败: %w", err)
+	}
+
+	return nil
+}
+
+// IsRevoked 判断会话是否已注销.
+func (s *sessionRevocationService) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	return s.sessions.Exists(ctx, sessionID, time.Now())
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	"go-react-template/pkg/model"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracedUserService 为 UserService 的每个方法创建一个 span.
+type tracedUserService struct {
+	next   UserService
+	tracer trace.Tracer
+}
+
+// NewTracedUserService 返回带链路追踪的 UserService，span 名称为 UserService.<方法名>.
+func NewTracedUserService(next UserService, tracer trace.Tracer) UserService {
+	return &tracedUserService{
+		next:   next,
+		tracer: tracer,
+	}
+}
+
+// Register 用户注册.
+func (s *tracedUserService) Register(ctx context.Context, req *model.UserRegisterRequest) (*model.UserResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.Register")
+	defer span.End()
+
+	user, err := s.next.Register(ctx, req)
+
+	return user, recordError(span, err)
+}
+
+// Login 用户登录.
+func (s *tracedUserService) Login(ctx context.Context, req *model.UserLoginRequest) (*LoginResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.Login")
+	defer span.End()
+
+	resp, err := s.next.Login(ctx, req)
+
+	return resp, recordError(span, err)
+}
+
+// GoogleLogin Google第三方登录.
+func (s *tracedUserService) GoogleLogin(ctx context.Context, req *model.GoogleLoginRequest) (*LoginResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.GoogleLogin")
+	defer span.End()
+
+	resp, err := s.next.GoogleLogin(ctx, req)
+
+	return resp, recordError(span, err)
+}
+
+// UpdateProfile 更新用户个人资料.
+func (s *tracedUserService) UpdateProfile(ctx context.Context, userID string, req *model.UserUpdateProfileRequest) (*model.UserResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.UpdateProfile")
+	defer span.End()
+
+	user, err := s.next.UpdateProfile(ctx, userID, req)
+
+	return user, recordError(span, err)
+}
+
+// GetUserByID 根据ID获取用户信息.
+func (s *tracedUserService) GetUserByID(ctx context.Context, id string) (*model.UserResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "UserService.GetUserByID")
+	defer span.End()
+
+	user, err := s.next.GetUserByID(ctx, id)
+
+	return user, recordError(span, err)
+}
+
+// ChangePassword 更改用户密码.
+func (s *tracedUserService) ChangePassword(ctx context.Context, userID string, req *model.UserChangePasswordRequest) error {
+	ctx, span := s.tracer.Start(ctx, "UserService.ChangePassword")
+	defer span.End()
+
+	return recordError(span, s.next.ChangePassword(ctx, userID, req))
+}
+
+// tracedLoginTokenService 为 LoginTokenService 的每个方法创建一个 span.
+type tracedLoginTokenService struct {
+	next   LoginTokenService
+	tracer trace.Tracer
+}
+
+// NewTracedLoginTokenService 返回带链路追踪的 LoginTokenService，span 名称为 LoginTokenService.<方法名>.
+func NewTracedLoginTokenService(next LoginTokenService, tracer trace.Tracer) LoginTokenService {
+	return &tracedLoginTokenService{
+		next:   next,
+		tracer: tracer,
+	}
+}
+
+// Issue 签发持久登录令牌.
+func (s *tracedLoginTokenService) Issue(ctx context.Context, userID string, client model.LoginClient) (string, error) {
+	ctx, span := s.tracer.Start(ctx, "LoginTokenService.Issue")
+	defer span.End()
+
+	token, err := s.next.Issue(ctx, userID, client)
+
+	return token, recordError(span, err)
+}
+
+// Authenticate 校验并轮换持久登录令牌.
+func (s *tracedLoginTokenService) Authenticate(ctx context.Context, token string, client model.LoginClient) (*model.User, string, error) {
+	ctx, span := s.tracer.Start(ctx, "LoginTokenService.Authenticate")
+	defer span.End()
+
+	user, newToken, err := s.next.Authenticate(ctx, token, client)
+
+	return user, newToken, recordError(span, err)
+}
+
+// Exists 判断持久登录令牌系列是否存在.
+func (s *tracedLoginTokenService) Exists(ctx context.Context, id string) (bool, error) {
+	ctx, span := s.tracer.Start(ctx, "LoginTokenService.Exists")
+	defer span.End()
+
+	exists, err := s.next.Exists(ctx, id)
+
+	return exists, recordError(span, err)
+}
+
+// List 获取用户的持久登录令牌.
+func (s *tracedLoginTokenService) List(ctx context.Context, userID, currentID string) ([]model.LoginTokenResponse, error) {
+	ctx, span := s.tracer.Start(ctx, "LoginTokenService.List")
+	defer span.End()
+
+	tokens, err := s.next.List(ctx, userID, currentID)
+
+	return tokens, recordError(span, err)
+}
+
+// Revoke 吊销持久登录令牌.
+func (s *tracedLoginTokenService) Revoke(ctx context.Context, userID, id string) error {
+	ctx, span := s.tracer.Start(ctx, "LoginTokenService.Revoke")
+	defer span.End()
+
+	return recordError(span, s.next.Revoke(ctx, userID, id))
+}
+
+// tracedSessionRevocationService 为 SessionRevocationService 的每个方法创建一个 span.
+type tracedSessionRevocationService struct {
+	next   SessionRevocationService
+	tracer trace.Tracer
+}
+
+// NewTracedSessionRevocationService 返回带链路追踪的 SessionRevocationService，span 名称为 SessionRevocationService.<方法名>.
+func NewTracedSessionRevocationService(next SessionRevocationService, tracer trace.Tracer) SessionRevocationService {
+	return &tracedSessionRevocationService{
+		next:   next,
+		tracer: tracer,
+	}
+}
+
+// Revoke 记录会话已注销.
+func (s *tracedSessionRevocationService) Revoke(ctx context.Context, sessionID, userID string, expiresAt time.Time) error {
+	ctx, span := s.tracer.Start(ctx, "SessionRevocationService.Revoke")
+	defer span.End()
+
+	return recordError(span, s.next.Revoke(ctx, sessionID, userID, expiresAt))
+}
+
+// IsRevoked 判断会话是否已注销.
+func (s *tracedSessionRevocationService) IsRevoked(ctx context.Context, sessionID string) (bool, error) {
+	ctx, span := s.tracer.Start(ctx, "SessionRevocationService.IsRevoked")
+	defer span.End()
+
+	revoked, err := s.next.IsRevoked(ctx, sessionID)
+
+	return revoked, recordError(span, err)
+}
+
+// recordError 将错误记录到 span 并原样返回.
+func recordError(span trace.Span, err error) error {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+
+	return err
+}
@@ -5,24 +5,30 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"strings"
 
-	"go-react-template/pkg/middleware"
 	"go-react-template/pkg/model"
 	"go-react-template/pkg/repo"
 
 	"golang.org/x/crypto/bcrypt"
-	"google.golang.org/api/idtoken"
 )
 
-// UserService 用户业务逻辑接口.
+// UserService 用户业务逻辑接口. 请求参数的格式由调用方按 validate 标签校验.
 type UserService interface {
-	Register(req *model.UserRegisterRequest) (*model.UserResponse, error)
-	Login(req *model.UserLoginRequest) (*LoginResponse, error)
-	GoogleLogin(req *model.GoogleLoginRequest) (*LoginResponse, error)
-	UpdateProfile(userID string, req *model.UserUpdateProfileRequest) (*model.UserResponse, error)
-	GetUserByID(id string) (*model.UserResponse, error)
-	ChangePassword(userID string, req *model.UserChangePasswordRequest) error
+	Register(ctx context.Context, req *model.UserRegisterRequest) (*model.UserResponse, error)
+	Login(ctx context.Context, req *model.UserLoginRequest) (*LoginResponse, error)
+	GoogleLogin(ctx context.Context, req *model.GoogleLoginRequest) (*LoginResponse, error)
+	UpdateProfile(ctx context.Context, userID string, req *model.UserUpdateProfileRequest) (*model.UserResponse, error)
+	GetUserByID(ctx context.Context, id string) (*model.UserResponse, error)
+	ChangePassword(ctx context.Context, userID string, req *model.UserChangePasswordRequest) error
+}
+
+// Recorder 业务指标记录器.
+type Recorder interface {
+	LoginSucceeded(loginType string)
+	LoginFailed(loginType string)
+	UserRegistered()
 }
 
 // LoginResponse 登录响应结构.
@@ -32,39 +38,33 @@ type LoginResponse struct {
 
 // userService 用户业务逻辑实现.
 type userService struct {
-	userRepo          repo.UserRepo
-	sessionMiddleware *middleware.SessionMiddleware
+	userRepo repo.UserRepo
+	tx       repo.TxManager
+	logger   *slog.Logger
+	recorder Recorder
+	google   GoogleTokenValidator
 }
 
+// maxUsernameAttempts Google用户自动生成用户名时的最大尝试次数.
+const maxUsernameAttempts = 100
+
 // NewUserService 创建用户业务逻辑实例.
-func NewUserService(userRepo repo.UserRepo) UserService {
+func NewUserService(userRepo repo.UserRepo, tx repo.TxManager, logger *slog.Logger, recorder Recorder, google GoogleTokenValidator) UserService {
 	return &userService{
-		userRepo:          userRepo,
-		sessionMiddleware: middleware.NewSessionMiddleware(),
+		userRepo: userRepo,
+		tx:       tx,
+		logger:   logger,
+		recorder: recorder,
+		google:   google,
 	}
 }
 
 // Register 用户注册.
-func (s *userService) Register(req *model.UserRegisterRequest) (*model.UserResponse, error) {
-	// 验证输入
-	if err := s.validateRegisterRequest(req); err != nil {
-		return nil, err
-	}
-
-	// 检查邮箱是否已存在
-	if _, err := s.userRepo.GetByEmail(req.Email); err == nil {
-		return nil, errors.New("邮箱已被注册")
-	}
-
-	// 检查用户名是否已存在
-	if _, err := s.userRepo.GetByUsername(req.Username); err == nil {
-		return nil, errors.New("用户名已被使用")
-	}
-
+func (s *userService) Register(ctx context.Context, req *model.UserRegisterRequest) (*model.UserResponse, error) {
 	// 加密密码
 	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.Password), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, errors.New("密码加密失败")
+		return nil, fmt.Errorf("密码加密失败: %w", err)
 	}
 
 	// 创建用户
@@ -74,38 +74,57 @@ func (s *userService) Register(req *model.UserRegisterRequest) (*model.UserRespo
 		Password: string(hashedPassword),
 	}
 
-	if err := s.userRepo.Create(user); err != nil {
-		return nil, fmt.Errorf("用户创建失败: %v", err)
+	// 邮箱、用户名的唯一性由数据库唯一索引保证，并发注册时不会产生重复用户
+	if err := s.userRepo.Create(ctx, user); err != nil {
+		if domainErr := mapDuplicate(err); domainErr != nil {
+			return nil, domainErr
+		}
+
+		return nil, fmt.Errorf("用户创建失败: %w", err)
 	}
 
+	s.logger.InfoContext(ctx, "用户注册成功", "new_user_id", user.ID)
+	s.recorder.UserRegistered()
+
 	response := user.ToResponse()
 
 	return &response, nil
 }
 
 // Login 用户登录.
-func (s *userService) Login(req *model.UserLoginRequest) (*LoginResponse, error) {
-	// 验证输入
-	if err := s.validateLoginRequest(req); err != nil {
-		return nil, err
-	}
-
+func (s *userService) Login(ctx context.Context, req *model.UserLoginRequest) (*LoginResponse, error) {
 	// 根据邮箱获取用户
-	user, err := s.userRepo.GetByEmail(req.Email)
+	user, err := s.userRepo.GetByEmail(ctx, req.Email)
 	if err != nil {
-		return nil, errors.New("邮箱或密码错误")
+		if !errors.Is(err, repo.ErrNotFound) {
+			s.logger.ErrorContext(ctx, "登录查询用户失败", "error", err)
+		}
+
+		s.logger.InfoContext(ctx, "登录失败", "reason", "user_not_found")
+		s.recorder.LoginFailed(string(model.LoginTypeLocal))
+
+		return nil, ErrInvalidCredentials
 	}
 
 	// 验证密码
 	if err := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.Password)); err != nil {
-		return nil, errors.New("邮箱或密码错误")
+		s.logger.InfoContext(ctx, "登录失败", "reason", "wrong_password", "login_user_id", user.ID)
+		s.recorder.LoginFailed(string(model.LoginTypeLocal))
+
+		return nil, ErrInvalidCredentials
 	}
 
 	// 检查用户是否被封禁
 	if user.IsBanned {
-		return nil, errors.New("账户已被封禁")
+		s.logger.WarnContext(ctx, "封禁用户尝试登录", "login_user_id", user.ID)
+		s.recorder.LoginFailed(string(user.LoginType))
+
+		return nil, ErrAccountBanned
 	}
 
+	s.logger.InfoContext(ctx, "登录成功", "login_user_id", user.ID, "login_type", user.LoginType)
+	s.recorder.LoginSucceeded(string(user.LoginType))
+
 	response := user.ToResponse()
 
 	return &LoginResponse{
@@ -114,9 +133,13 @@ func (s *userService) Login(req *model.UserLoginRequest) (*LoginResponse, error)
 }
 
 // GetUserByID 根据ID获取用户信息.
-func (s *userService) GetUserByID(id string) (*model.UserResponse, error) {
-	user, err := s.userRepo.GetByID(id)
+func (s *userService) GetUserByID(ctx context.Context, id string) (*model.UserResponse, error) {
+	user, err := s.userRepo.GetByID(ctx, id)
 	if err != nil {
+		if errors.Is(err, repo.ErrNotFound) {
+			return nil, ErrUserNotFound
+		}
+
 		return nil, err
 	}
 
@@ -125,63 +148,15 @@ func (s *userService) GetUserByID(id string) (*model.UserResponse, error) {
 	return &response, nil
 }
 
-// validateRegisterRequest 验证注册请求.
-func (s *userService) validateRegisterRequest(req *model.UserRegisterRequest) error {
-	if req.Username == "" {
-		return errors.New("用户名不能为空")
-	}
-
-	if len(req.Username) < 3 || len(req.Username) > 50 {
-		return errors.New("用户名长度必须在3-50个字符之间")
-	}
-
-	if req.Email == "" {
-		return errors.New("邮箱不能为空")
-	}
-
-	if !strings.Contains(req.Email, "@") {
-		return errors.New("邮箱格式不正确")
-	}
-
-	if req.Password == "" {
-		return errors.New("密码不能为空")
-	}
-
-	if len(req.Password) < 6 {
-		return errors.New("密码长度不能少于6个字符")
-	}
-
-	return nil
-}
-
-// validateLoginRequest 验证登录请求.
-func (s *userService) validateLoginRequest(req *model.UserLoginRequest) error {
-	if req.Email == "" {
-		return errors.New("邮箱不能为空")
-	}
-
-	if !strings.Contains(req.Email, "@") {
-		return errors.New("邮箱格式不正确")
-	}
-
-	if req.Password == "" {
-		return errors.New("密码不能为空")
-	}
-
-	return nil
-}
-
 // GoogleLogin Google第三方登录.
-func (s *userService) GoogleLogin(req *model.GoogleLoginRequest) (*LoginResponse, error) {
-	// 验证输入
-	if req.IDToken == "" {
-		return nil, errors.New("Google ID Token不能为空")
-	}
-
+func (s *userService) GoogleLogin(ctx context.Context, req *model.GoogleLoginRequest) (*LoginResponse, error) {
 	// 验证Google ID Token
-	payload, err := idtoken.Validate(context.Background(), req.IDToken, "")
+	payload, err := s.google.Validate(ctx, req.IDToken, "")
 	if err != nil {
-		return nil, fmt.Errorf("Google ID Token验证失败: %v", err)
+		s.logger.WarnContext(ctx, "Google ID Token验证失败", "error", err)
+		s.recorder.LoginFailed(string(model.LoginTypeGoogle))
+
+		return nil, ErrInvalidGoogleToken
 	}
 
 	// 从payload中提取用户信息
@@ -191,57 +166,18 @@ func (s *userService) GoogleLogin(req *model.GoogleLoginRequest) (*LoginResponse
 	picture, _ := payload.Claims["picture"].(string) //nolint:errcheck
 
 	if email == "" {
-		return nil, errors.New("无法获取Google账户邮箱")
-	}
-
-	// 检查是否已存在Google用户
-	user, err := s.userRepo.GetByGoogleID(googleID)
-	if err == nil {
-		// 检查用户是否被封禁
-		if user.IsBanned {
-			return nil, errors.New("账户已被封禁")
-		}
-		// 用户已存在，直接登录
-		response := user.ToResponse()
-
-		return &LoginResponse{
-			User: &response,
-		}, nil
+		s.recorder.LoginFailed(string(model.LoginTypeGoogle))
+		return nil, ErrGoogleEmailMissing
 	}
 
-	// 检查邮箱是否已被本地用户使用
-	existingUser, err := s.userRepo.GetByEmail(email)
-	if err == nil {
-		// 邮箱已存在，但不是Google用户，需要绑定
-		if existingUser.LoginType == model.LoginTypeLocal {
-			return nil, errors.New("该邮箱已被注册，请使用密码登录或联系管理员")
-		}
-	}
-
-	// 创建新的Google用户
+	// 创建新的Google用户时使用的用户名
 	username := name
 	if username == "" {
 		// 如果没有名字，使用邮箱前缀作为用户名
 		username = strings.Split(email, "@")[0]
 	}
 
-	// 确保用户名唯一
-	originalUsername := username
-	counter := 1
-
-	for {
-		if _, err := s.userRepo.GetByUsername(username); err != nil {
-			// 用户名不存在，可以使用
-			break
-		}
-		// 用户名已存在，添加数字后缀
-		username = fmt.Sprintf("%s%d", originalUsername, counter)
-		counter++
-	}
-
-	// 创建新用户
 	newUser := &model.User{
-		Username:  username,
 		Email:     email,
 		AvatarURL: picture,
 		GoogleID:  &googleID,
@@ -249,136 +185,188 @@ func (s *userService) GoogleLogin(req *model.GoogleLoginRequest) (*LoginResponse
 		// Google用户不需要密码
 	}
 
-	if err := s.userRepo.Create(newUser); err != nil {
-		return nil, errors.New("用户创建失败")
-	}
+	var user *model.User
 
-	response := newUser.ToResponse()
+	// 同一Google账户并发首次登录时，后提交的事务会因 google_id 唯一约束失败，重新执行一次即可读到已创建的用户
+	for attempt := 0; attempt < 2; attempt++ {
+		err = s.tx.WithinTx(ctx, func(ctx context.Context, repos repo.Repos) error {
+			var txErr error
 
-	return &LoginResponse{
-		User: &response,
-	}, nil
-}
+			user, txErr = s.findOrCreateGoogleUser(ctx, repos, newUser, username)
 
-// ChangePassword 更改用户密码.
-func (s *userService) ChangePassword(userID string, req *model.UserChangePasswordRequest) error {
-	// 验证输入
-	if err := s.validateChangePasswordRequest(req); err != nil {
-		return err
+			return txErr
+		})
+		if !repo.IsDuplicate(err, "google_id") {
+			break
+		}
 	}
 
-	// 获取当前用户
-	user, err := s.userRepo.GetByID(userID)
 	if err != nil {
-		return errors.New("用户不存在")
+		s.recorder.LoginFailed(string(model.LoginTypeGoogle))
+		return nil, err
 	}
 
-	// 验证旧密码
-	if errCompare := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); errCompare != nil {
-		return errors.New("旧密码错误")
-	}
+	// 检查用户是否被封禁
+	if user.IsBanned {
+		s.logger.WarnContext(ctx, "封禁用户尝试登录", "login_user_id", user.ID)
+		s.recorder.LoginFailed(string(user.LoginType))
 
-	// 加密新密码
-	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
-	if err != nil {
-		return errors.New("密码加密失败")
+		return nil, ErrAccountBanned
 	}
 
-	// 更新密码
-	user.Password = string(hashedPassword)
-	if err := s.userRepo.Update(user); err != nil {
-		return errors.New("密码更新失败")
-	}
+	s.logger.InfoContext(ctx, "登录成功", "login_user_id", user.ID, "login_type", user.LoginType)
+	s.recorder.LoginSucceeded(string(user.LoginType))
 
-	return nil
+	response := user.ToResponse()
+
+	return &LoginResponse{
+		User: &response,
+	}, nil
 }
 
-// validateChangePasswordRequest 验证更改密码请求.
-func (s *userService) validateChangePasswordRequest(req *model.UserChangePasswordRequest) error {
-	if req.OldPassword == "" {
-		return errors.New("旧密码不能为空")
+// findOrCreateGoogleUser 在事务中查找Google用户，不存在时创建.
+func (s *userService) findOrCreateGoogleUser(ctx context.Context, repos repo.Repos, newUser *model.User, username string) (*model.User, error) {
+	// 检查是否已存在Google用户
+	user, err := repos.Users.GetByGoogleID(ctx, *newUser.GoogleID)
+	if err == nil {
+		return user, nil
 	}
 
-	if req.NewPassword == "" {
-		return errors.New("新密码不能为空")
+	if !errors.Is(err, repo.ErrNotFound) {
+		return nil, err
 	}
 
-	if len(req.NewPassword) < 6 {
-		return errors.New("新密码长度不能少于6个字符")
+	// 检查邮箱是否已被本地用户使用
+	existingUser, err := repos.Users.GetByEmail(ctx, newUser.Email)
+	if err == nil && existingUser.LoginType == model.LoginTypeLocal {
+		// 邮箱已存在，但不是Google用户，需要绑定
+		return nil, ErrEmailBoundToLocal
 	}
 
-	if req.OldPassword == req.NewPassword {
-		return errors.New("新密码不能与旧密码相同")
-	}
+	// 依次尝试添加数字后缀，直到用户名不冲突；每次插入使用保存点，失败不会中止外层事务
+	for counter := 0; counter < maxUsernameAttempts; counter++ {
+		newUser.ID = "" // 每次插入重新生成ID
+		newUser.Username = username
+		if counter > 0 {
+			newUser.Username = fmt.Sprintf("%s%d", username, counter)
+		}
 
-	return nil
-}
+		err = s.tx.WithinTx(ctx, func(ctx context.Context, repos repo.Repos) error {
+			return repos.Users.Create(ctx, newUser)
+		})
+		if err == nil {
+			s.logger.InfoContext(ctx, "创建Google用户", "new_user_id", newUser.ID, "username", newUser.Username)
+			return newUser, nil
+		}
 
-// UpdateProfile 更新用户个人资料.
-func (s *userService) UpdateProfile(userID string, req *model.UserUpdateProfileRequest) (*model.UserResponse, error) {
-	// 验证输入
-	if err := s.validateUpdateProfileRequest(req); err != nil {
-		return nil, err
+		switch {
+		case repo.IsDuplicate(err, "username"):
+			continue
+		case repo.IsDuplicate(err, "google_id"):
+			return nil, err
+		case repo.IsDuplicate(err, "email"):
+			return nil, ErrEmailTaken
+		default:
+			return nil, fmt.Errorf("Google用户创建失败: %w", err)
+		}
 	}
 
-	// 获取当前用户
-	user, err := s.userRepo.GetByID(userID)
+	return nil, ErrUsernameTaken
+}
+
+// ChangePassword 更改用户密码.
+func (s *userService) ChangePassword(ctx context.Context, userID string, req *model.UserChangePasswordRequest) error {
+	// 加密新密码，放在事务外避免长时间持有事务
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(req.NewPassword), bcrypt.DefaultCost)
 	if err != nil {
-		return nil, errors.New("用户不存在")
+		return fmt.Errorf("密码加密失败: %w", err)
 	}
 
-	// 如果要更新用户名，检查是否已被使用
-	if req.Username != "" && req.Username != user.Username {
-		if _, err := s.userRepo.GetByUsername(req.Username); err == nil {
-			return nil, errors.New("用户名已被使用")
-		}
+	err = s.tx.WithinTx(ctx, func(ctx context.Context, repos repo.Repos) error {
+		// 获取当前用户
+		user, err := repos.Users.GetByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return ErrUserNotFound
+			}
 
-		user.Username = req.Username
-	}
+			return fmt.Errorf("获取用户失败: %w", err)
+		}
 
-	// 如果要更新邮箱，检查是否已被使用
-	if req.Email != "" && req.Email != user.Email {
-		if _, err := s.userRepo.GetByEmail(req.Email); err == nil {
-			return nil, errors.New("邮箱已被注册")
+		// 验证旧密码
+		if errCompare := bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(req.OldPassword)); errCompare != nil {
+			return ErrWrongPassword
 		}
 
-		user.Email = req.Email
-	}
+		// 更新密码
+		user.Password = string(hashedPassword)
+		if err := repos.Users.Update(ctx, user); err != nil {
+			return fmt.Errorf("密码更新失败: %w", err)
+		}
 
-	// 更新其他字段
-	if req.Bio != "" {
-		user.Bio = req.Bio
-	}
+		// 吊销所有持久登录令牌，其他设备需要使用新密码重新登录
+		if err := repos.LoginTokens.DeleteByUser(ctx, userID); err != nil {
+			return fmt.Errorf("持久登录令牌吊销失败: %w", err)
+		}
 
-	// 保存更新
-	if err := s.userRepo.Update(user); err != nil {
-		return nil, errors.New("更新失败")
+		return nil
+	})
+	if err != nil {
+		return err
 	}
 
-	response := user.ToResponse()
+	s.logger.InfoContext(ctx, "密码已修改")
 
-	return &response, nil
+	return nil
 }
 
-// validateUpdateProfileRequest 验证更新个人资料请求.
-func (s *userService) validateUpdateProfileRequest(req *model.UserUpdateProfileRequest) error {
-	if req.Username != "" {
-		if len(req.Username) < 3 || len(req.Username) > 50 {
-			return errors.New("用户名长度必须在3-50个字符之间")
+// UpdateProfile 更新用户个人资料.
+func (s *userService) UpdateProfile(ctx context.Context, userID string, req *model.UserUpdateProfileRequest) (*model.UserResponse, error) {
+	var user *model.User
+
+	err := s.tx.WithinTx(ctx, func(ctx context.Context, repos repo.Repos) error {
+		// 获取当前用户
+		var err error
+
+		user, err = repos.Users.GetByID(ctx, userID)
+		if err != nil {
+			if errors.Is(err, repo.ErrNotFound) {
+				return ErrUserNotFound
+			}
+
+			return fmt.Errorf("获取用户失败: %w", err)
 		}
-	}
 
-	if req.Email != "" {
-		if !strings.Contains(req.Email, "@") {
-			return errors.New("邮箱格式不正确")
+		// 用户名、邮箱的唯一性由数据库唯一索引保证
+		if req.Username != "" {
+			user.Username = req.Username
 		}
-	}
 
-	if req.Bio != "" {
-		if len(req.Bio) > 500 {
-			return errors.New("个人简介不能超过500个字符")
+		if req.Email != "" {
+			user.Email = req.Email
 		}
+
+		// 更新其他字段
+		if req.Bio != "" {
+			user.Bio = req.Bio
+		}
+
+		// 保存更新
+		if err := repos.Users.Update(ctx, user); err != nil {
+			if domainErr := mapDuplicate(err); domainErr != nil {
+				return domainErr
+			}
+
+			return fmt.Errorf("个人资料更新失败: %w", err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	return nil
+	response := user.ToResponse()
+
+	return &response, nil
 }
@@ -0,0 +1,298 @@
+// Package static 前端静态文件服务：按 Accept-Encoding 返回构建时预压缩的 .br/.gz 文件，启动时计算强 ETag，
+// 支持 If-None-Match 条件请求和 Range 范围请求
+package static
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 内容编码，按优先级排列.
+const (
+	encodingBrotli   = "br"
+	encodingGzip     = "gzip"
+	encodingIdentity = ""
+)
+
+// encodings 预压缩文件的内容编码及其文件名后缀，按优先级排列.
+var encodings = []struct {
+	name   string
+	suffix string
+}{
+	{encodingBrotli, ".br"},
+	{encodingGzip, ".gz"},
+}
+
+// Server 静态文件服务. 文件列表和 ETag 在创建时确定，之后对文件的修改需要重启后生效.
+type Server struct {
+	fsys  fs.FS
+	files map[string]*file
+}
+
+// file 一个静态文件及其预压缩版本.
+type file struct {
+	contentType string
+	variants    []variant // 按 encodings 的优先级排列，最后一个为未压缩的原文件
+}
+
+// variant 文件的一种编码.
+type variant struct {
+	name     string // 在 fsys 中的文件名
+	encoding string
+	etag     string
+	modTime  time.Time
+}
+
+// New 遍历 fsys 中的所有文件并计算 ETag. 与某个文件同名并带有 .br 或 .gz 后缀的文件作为该文件的预压缩版本，
+// 不单独提供.
+func New(fsys fs.FS) (*Server, error) {
+	names := make(map[string]struct{})
+
+	err := fs.WalkDir(fsys, ".", func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if d.Type().IsRegular() {
+			names[name] = struct{}{}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("遍历静态文件失败: %w", err)
+	}
+
+	s := &Server{
+		fsys:  fsys,
+		files: make(map[string]*file, len(names)),
+	}
+
+	for name := range names {
+		if isCompressed(name, names) {
+			continue
+		}
+
+		f := &file{contentType: contentType(name)}
+
+		for _, enc := range encodings {
+			if _, ok := names[name+enc.suffix]; !ok {
+				continue
+			}
+
+			v, err := newVariant(fsys, name+enc.suffix, enc.name)
+			if err != nil {
+				return nil, err
+			}
+
+			f.variants = append(f.variants, v)
+		}
+
+		v, err := newVariant(fsys, name, encodingIdentity)
+		if err != nil {
+			return nil, err
+		}
+
+		f.variants = append(f.variants, v)
+		s.files[name] = f
+	}
+
+	return s, nil
+}
+
+// isCompressed 判断 name 是否为另一个文件的预压缩版本.
+func isCompressed(name string, names map[string]struct{}) bool {
+	for _, enc := range encodings {
+		if base, ok := strings.CutSuffix(name, enc.suffix); ok {
+			if _, exists := names[base]; exists {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// contentType 按扩展名返回文件的 Content-Type. 预压缩的内容无法嗅探，未知扩展名按二进制文件处理.
+func contentType(name string) string {
+	if ct := mime.TypeByExtension(path.Ext(name)); ct != "" {
+		return ct
+	}
+
+	return "application/octet-stream"
+}
+
+// newVariant 读取文件并以内容的 SHA-256 作为强 ETag. 不同编码的内容不同，ETag 也不同.
+func newVariant(fsys fs.FS, name, encoding string) (variant, error) {
+	f, err := fsys.Open(name)
+	if err != nil {
+		return variant{}, fmt.Errorf("读取静态文件 %s 失败: %w", name, err)
+	}
+	defer f.Close() //nolint:errcheck // 只读文件
+
+	info, err := f.Stat()
+	if err != nil {
+		return variant{}, fmt.Errorf("读取静态文件 %s 失败: %w", name, err)
+	}
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return variant{}, fmt.Errorf("读取静态文件 %s 失败: %w", name, err)
+	}
+
+	return variant{
+		name:     name,
+		encoding: encoding,
+		etag:     `"` + hex.EncodeToString(h.Sum(nil)[:16]) + `"`,
+		modTime:  info.ModTime(),
+	}, nil
+}
+
+// Lookup 将URL路径转换为文件名并返回文件是否存在. .. 在根目录处截断，根路径和目录不算文件.
+func (s *Server) Lookup(urlPath string) (string, bool) {
+	name := strings.TrimPrefix(path.Clean("/"+urlPath), "/")
+	_, ok := s.files[name]
+
+	return name, ok
+}
+
+// ServeFile 返回文件 name，文件不存在时返回 fs.ErrNotExist.
+//
+// 按请求的 Accept-Encoding 选择预压缩版本，有预压缩版本的文件设置 Vary: Accept-Encoding. 条件请求和范围请求由
+// http.ServeContent 处理，范围按所选编码的内容计算.
+func (s *Server) ServeFile(w http.ResponseWriter, r *http.Request, name string) error {
+	f, ok := s.files[name]
+	if !ok {
+		return fs.ErrNotExist
+	}
+
+	v := f.negotiate(r.Header.Get("Accept-Encoding"))
+
+	content, err := s.open(v.name)
+	if err != nil {
+		return err
+	}
+	defer content.Close() //nolint:errcheck // 只读文件
+
+	header := w.Header()
+	if len(f.variants) > 1 {
+		header.Add("Vary", "Accept-Encoding")
+	}
+
+	if v.encoding != encodingIdentity {
+		header.Set("Content-Encoding", v.encoding)
+	}
+
+	header.Set("Content-Type", f.contentType)
+	header.Set("ETag", v.etag)
+
+	http.ServeContent(w, r, name, v.modTime, content)
+
+	return nil
+}
+
+// ReadFile 读取文件的原始内容，用于需要在返回前处理内容的文件（如替换 HTML 中的占位符）.
+func (s *Server) ReadFile(name string) ([]byte, error) {
+	return fs.ReadFile(s.fsys, name)
+}
+
+// readSeekCloser 可随机读取的文件内容.
+type readSeekCloser interface {
+	io.ReadSeeker
+	io.Closer
+}
+
+// open 打开文件. 文件系统的实现不支持 Seek 时读入内存.
+func (s *Server) open(name string) (readSeekCloser, error) {
+	f, err := s.fsys.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if rs, ok := f.(readSeekCloser); ok {
+		return rs, nil
+	}
+
+	defer f.Close() //nolint:errcheck // 只读文件
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return nil, err
+	}
+
+	return nopCloser{bytes.NewReader(data)}, nil
+}
+
+// nopCloser 为 io.ReadSeeker 添加空的 Close 方法.
+type nopCloser struct {
+	io.ReadSeeker
+}
+
+// Close 不做任何操作.
+func (nopCloser) Close() error { return nil }
+
+// negotiate 按 Accept-Encoding 选择客户端接受且优先级最高的版本，都不接受时返回原文件.
+func (f *file) negotiate(acceptEncoding string) variant {
+	if acceptEncoding == "" || len(f.variants) == 1 {
+		return f.variants[len(f.variants)-1]
+	}
+
+	accepted := parseAcceptEncoding(acceptEncoding)
+
+	for _, v := range f.variants[:len(f.variants)-1] {
+		q, ok := accepted[v.encoding]
+		if !ok {
+			q = accepted["*"]
+		}
+
+		if q > 0 {
+			return v
+		}
+	}
+
+	return f.variants[len(f.variants)-1]
+}
+
+// parseAcceptEncoding 解析 Accept-Encoding 请求头，返回各编码的权重. 未指定 q 时权重为 1，格式错误的权重按 0 处理.
+func parseAcceptEncoding(header string) map[string]float64 {
+	accepted := make(map[string]float64)
+
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(part, ";")
+
+		coding = strings.ToLower(strings.TrimSpace(coding))
+		if coding == "" {
+			continue
+		}
+
+		q := 1.0
+
+		for _, param := range strings.Split(params, ";") {
+			key, value, ok := strings.Cut(param, "=")
+			if !ok || !strings.EqualFold(strings.TrimSpace(key), "q") {
+				continue
+			}
+
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+			if err != nil {
+				parsed = 0
+			}
+
+			q = parsed
+		}
+
+		accepted[coding] = q
+	}
+
+	return accepted
+}
@@ -0,0 +1,102 @@
+// Package tracing OpenTelemetry 链路追踪：根据配置创建 TracerProvider 并使用 W3C Trace Context 传播
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"go-react-template/configs"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// 导出器类型.
+const (
+	ExporterNone   = "none"
+	ExporterStdout = "stdout"
+	ExporterOTLP   = "otlp"
+)
+
+// Provider 链路追踪组件，持有 TracerProvider 和上下文传播器.
+type Provider struct {
+	trace.TracerProvider
+	Propagator propagation.TextMapPropagator
+
+	shutdown func(context.Context) error
+}
+
+// New 根据配置创建链路追踪组件. 导出器为 none 时使用不产生任何数据的 TracerProvider.
+func New(ctx context.Context, cfg configs.TracingConfig) (*Provider, error) {
+	p := &Provider{
+		// W3C Trace Context 和 Baggage
+		Propagator: propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}),
+	}
+
+	var spanProcessor sdktrace.SpanProcessor
+
+	switch cfg.Exporter {
+	case ExporterNone:
+		p.TracerProvider = noop.NewTracerProvider()
+		p.shutdown = func(context.Context) error { return nil }
+
+		return p, nil
+	case ExporterStdout:
+		exporter, err := stdouttrace.New(stdouttrace.WithWriter(os.Stdout))
+		if err != nil {
+			return nil, fmt.Errorf("创建stdout导出器失败: %w", err)
+		}
+
+		// 本地调试时同步输出，便于和日志对照
+		spanProcessor = sdktrace.NewSimpleSpanProcessor(exporter)
+	case ExporterOTLP:
+		opts := []otlptracehttp.Option{}
+		if cfg.Endpoint != "" {
+			opts = append(opts, otlptracehttp.WithEndpoint(cfg.Endpoint))
+		}
+
+		if cfg.Insecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+
+		exporter, err := otlptracehttp.New(ctx, opts...)
+		if err != nil {
+			return nil, fmt.Errorf("创建OTLP导出器失败: %w", err)
+		}
+
+		spanProcessor = sdktrace.NewBatchSpanProcessor(exporter)
+	default:
+		return nil, fmt.Errorf("不支持的链路追踪导出器: %s", cfg.Exporter)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", cfg.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("创建链路追踪资源失败: %w", err)
+	}
+
+	tp := sdktrace.NewTracerProvider(
+		sdktrace.WithResource(res),
+		sdktrace.WithSpanProcessor(spanProcessor),
+		// 上游已经决定采样时沿用上游的决定
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+
+	p.TracerProvider = tp
+	p.shutdown = tp.Shutdown
+
+	return p, nil
+}
+
+// Shutdown 导出尚未发送的数据并关闭.
+func (p *Provider) Shutdown(ctx context.Context) error {
+	return p.shutdown(ctx)
+}
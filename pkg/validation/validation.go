@@ -0,0 +1,162 @@
+// Package validation 基于 validate 结构体标签的请求校验，作为 Echo 的 Validator 和 Binder 使用
+package validation
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"go-react-template/pkg/apperr"
+
+	"github.com/go-playground/validator/v10"
+	"github.com/labstack/echo/v4"
+)
+
+// usernamePattern 用户名只能包含字母（含中文等）、数字、下划线、连字符和点.
+var usernamePattern = regexp.MustCompile(`^[\p{L}\p{N}_.-]+$`)
+
+// reservedUsernames 保留用户名，不区分大小写，避免用户冒充系统账户或与路由冲突.
+var reservedUsernames = map[string]struct{}{
+	"admin":         {},
+	"administrator": {},
+	"root":          {},
+	"system":        {},
+	"support":       {},
+	"security":      {},
+	"moderator":     {},
+	"official":      {},
+	"api":           {},
+	"www":           {},
+	"me":            {},
+	"null":          {},
+	"undefined":     {},
+}
+
+// Validator 请求校验器，实现 echo.Validator. 校验失败时返回带字段错误的 apperr.ErrValidation，
+// 字段名使用 json 标签，错误码为校验规则名.
+type Validator struct {
+	validate *validator.Validate
+}
+
+// New 创建请求校验器并注册自定义规则：
+//   - username: 用户名字符集
+//   - notreserved: 不能使用保留用户名
+func New() *Validator {
+	v := validator.New(validator.WithRequiredStructEnabled())
+	v.RegisterTagNameFunc(jsonName)
+
+	// 注册固定的规则名，只有在规则名重复时才会出错
+	_ = v.RegisterValidation("username", func(fl validator.FieldLevel) bool { //nolint:errcheck
+		return usernamePattern.MatchString(fl.Field().String())
+	})
+	_ = v.RegisterValidation("notreserved", func(fl validator.FieldLevel) bool { //nolint:errcheck
+		_, reserved := reservedUsernames[strings.ToLower(fl.Field().String())]
+		return !reserved
+	})
+
+	return &Validator{validate: v}
+}
+
+// Validate 校验结构体.
+func (v *Validator) Validate(s interface{}) error {
+	err := v.validate.Struct(s)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if !errors.As(err, &fieldErrs) {
+		return err
+	}
+
+	fields := make([]apperr.FieldError, len(fieldErrs))
+	for i, fe := range fieldErrs {
+		fields[i] = apperr.FieldError{
+			Field: fe.Field(),
+			Code:  fe.Tag(),
+			Param: fe.Param(),
+		}
+
+		// 跨字段规则（如 nefield）的参数是结构体字段名，转换为 json 字段名与 Field 保持一致
+		if strings.HasSuffix(fe.Tag(), "field") {
+			fields[i].Param = jsonFieldName(reflect.TypeOf(s), fe.Param())
+		}
+	}
+
+	return apperr.Validation(fields...)
+}
+
+// Binder 在绑定请求后执行校验. 请求体不是合法 JSON、字段类型不匹配时返回 apperr.ErrBadRequest，
+// 请求体过大返回 413，不支持的 Content-Type 等其他HTTP错误保留原状态码.
+type Binder struct {
+	echo.DefaultBinder
+}
+
+// Bind 绑定并校验请求.
+func (b *Binder) Bind(i interface{}, c echo.Context) error {
+	if err := b.DefaultBinder.Bind(i, c); err != nil {
+		return bindError(err)
+	}
+
+	return c.Validate(i)
+}
+
+// bindError 转换绑定错误. 只有请求内容本身格式错误时才返回 apperr.ErrBadRequest，其他错误原样返回，
+// 由错误处理器按状态码处理并在请求日志中保留原因.
+func bindError(err error) error {
+	var (
+		maxBytesErr *http.MaxBytesError
+		syntaxErr   *json.SyntaxError
+		typeErr     *json.UnmarshalTypeError
+		numErr      *strconv.NumError
+		httpErr     *echo.HTTPError
+	)
+
+	switch {
+	case errors.As(err, &maxBytesErr):
+		return echo.ErrStatusRequestEntityTooLarge.WithInternal(err)
+	case errors.As(err, &syntaxErr), errors.As(err, &typeErr), errors.As(err, &numErr),
+		errors.Is(err, io.ErrUnexpectedEOF):
+		return apperr.ErrBadRequest
+	case errors.As(err, &httpErr) && httpErr.Code != http.StatusBadRequest:
+		return httpErr
+	default:
+		return err
+	}
+}
+
+// jsonName 使用 json 标签作为字段名，没有 json 标签时使用结构体字段名.
+func jsonName(field reflect.StructField) string {
+	name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+
+	switch name {
+	case "-":
+		return ""
+	case "":
+		return field.Name
+	default:
+		return name
+	}
+}
+
+// jsonFieldName 返回结构体字段对应的 json 字段名，找不到字段时原样返回.
+func jsonFieldName(typ reflect.Type, structField string) string {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	if typ.Kind() != reflect.Struct {
+		return structField
+	}
+
+	if field, ok := typ.FieldByName(structField); ok {
+		return jsonName(field)
+	}
+
+	return structField
+}
@@ -0,0 +1,84 @@
+package validation
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"go-react-template/pkg/apperr"
+
+	"github.com/labstack/echo/v4"
+)
+
+// bindRequest 测试绑定使用的请求体.
+type bindRequest struct {
+	Name string `json:"name" validate:"required"`
+	Age  int    `json:"age"`
+}
+
+func TestBinderBind(t *testing.T) {
+	e := echo.New()
+	e.Validator = New()
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		maxBytes    int64
+		wantStatus  int   // 期望的 echo.HTTPError 状态码
+		wantErr     error // 期望的应用错误
+	}{
+		{name: "合法请求", contentType: echo.MIMEApplicationJSON, body: `{"name":"alice","age":1}`},
+		{name: "JSON 语法错误", contentType: echo.MIMEApplicationJSON, body: `{"name":`, wantErr: apperr.ErrBadRequest},
+		{name: "JSON 格式错误", contentType: echo.MIMEApplicationJSON, body: `{"name" "alice"}`, wantErr: apperr.ErrBadRequest},
+		{name: "字段类型不匹配", contentType: echo.MIMEApplicationJSON, body: `{"name":"alice","age":"1"}`, wantErr: apperr.ErrBadRequest},
+		{name: "不支持的 Content-Type", contentType: echo.MIMETextPlain, body: `name=alice`, wantStatus: http.StatusUnsupportedMediaType},
+		{name: "请求体过大", contentType: echo.MIMEApplicationJSON, body: `{"name":"` + strings.Repeat("a", 64) + `"}`, maxBytes: 16, wantStatus: http.StatusRequestEntityTooLarge},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tt.body))
+			req.Header.Set(echo.HeaderContentType, tt.contentType)
+
+			rec := httptest.NewRecorder()
+			if tt.maxBytes > 0 {
+				req.Body = http.MaxBytesReader(rec, req.Body, tt.maxBytes)
+			}
+
+			err := (&Binder{}).Bind(&bindRequest{}, e.NewContext(req, rec))
+
+			var httpErr *echo.HTTPError
+
+			switch {
+			case tt.wantErr != nil:
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("Bind() = %v, want %v", err, tt.wantErr)
+				}
+			case tt.wantStatus != 0:
+				if !errors.As(err, &httpErr) || httpErr.Code != tt.wantStatus {
+					t.Fatalf("Bind() = %v, want HTTP %d", err, tt.wantStatus)
+				}
+			case err != nil:
+				t.Fatalf("Bind() = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestBinderBindValidates(t *testing.T) {
+	e := echo.New()
+	e.Validator = New()
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(`{"age":1}`))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+
+	err := (&Binder{}).Bind(&bindRequest{}, e.NewContext(req, httptest.NewRecorder()))
+
+	appErr, ok := apperr.As(err)
+	if !ok || len(appErr.Fields) != 1 || appErr.Fields[0].Field != "name" {
+		t.Fatalf("Bind() = %v, want 字段 name 的校验错误", err)
+	}
+}
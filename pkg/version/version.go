@@ -0,0 +1,11 @@
+// Package version 构建信息，构建时通过 -ldflags 注入:
+//
+//	go build -ldflags "-X go-react-template/pkg/version.Version=v1.2.0 -X go-react-template/pkg/version.Commit=$(git rev-parse --short HEAD)"
+package version
+
+// 构建信息，未注入时为默认值.
+var (
+	Version   = "dev"
+	Commit    = "unknown"
+	BuildTime = "unknown"
+)
@@ -1,7 +1,7 @@
 #!/bin/bash
 
 # Go + React 全栈项目构建脚本
-# 构建前端静态文件并打包成单一二进制程序
+# 构建前端静态文件并嵌入单一二进制程序
 
 set -e  # 遇到错误立即退出
 
@@ -14,7 +14,6 @@ echo "📁 项目根目录: $PROJECT_ROOT"
 # 清理之前的构建文件
 echo "🧹 清理之前的构建文件..."
 rm -rf "$PROJECT_ROOT/web/dist"
-rm -rf "$PROJECT_ROOT/static"
 rm -f "$PROJECT_ROOT/server"
 
 # 构建前端
@@ -29,13 +28,7 @@ fi
 
 echo "✅ 前端构建完成"
 
-# 将前端构建文件复制到后端静态文件目录
-echo "📦 复制前端静态文件到后端..."
 cd "$PROJECT_ROOT"
-mkdir -p static
-cp -r web/dist/* static/
-
-echo "✅ 静态文件复制完成"
 
 # 代码质量检查
 echo "🔍 运行代码质量检查..."
@@ -55,8 +48,14 @@ fi
 
 # 构建后端 Go 程序
 echo "🔨 构建后端 Go 程序..."
-# go-sqlite3 需要 CGO 支持
-CGO_ENABLED=1 go build -o server main.go
+# 构建信息，可通过同名环境变量覆盖
+VERSION="${VERSION:-$(git describe --tags --always --dirty 2>/dev/null || echo dev)}"
+COMMIT="${COMMIT:-$(git rev-parse --short HEAD 2>/dev/null || echo unknown)}"
+BUILD_TIME="${BUILD_TIME:-$(date -u +%Y-%m-%dT%H:%M:%SZ)}"
+LDFLAGS="-X go-react-template/pkg/version.Version=$VERSION -X go-react-template/pkg/version.Commit=$COMMIT -X go-react-template/pkg/version.BuildTime=$BUILD_TIME"
+echo "🏷️  版本: $VERSION ($COMMIT)"
+# go-sqlite3 需要 CGO 支持，-tags embed 将 web/dist 嵌入二进制程序
+CGO_ENABLED=1 go build -tags embed -ldflags "$LDFLAGS" -o server main.go
 
 if [ ! -f "server" ]; then
     echo "❌ 后端构建失败"
@@ -69,8 +68,7 @@ echo "✅ 后端构建完成"
 echo ""
 echo "🎉 构建完成！"
 echo "📊 构建结果:"
-echo "   - 可执行文件: $PROJECT_ROOT/server"
-echo "   - 静态文件目录: $PROJECT_ROOT/static/"
+echo "   - 可执行文件: $PROJECT_ROOT/server（已嵌入前端资源）"
 echo "   - 文件大小: $(du -h server | cut -f1)"
 echo ""
 echo "🚀 运行方式:"
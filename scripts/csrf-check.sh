@@ -0,0 +1,88 @@
+#!/bin/bash
+
+# CSRF 防护检查脚本
+# 对运行中的服务模拟跨站请求伪造场景，确认修改数据的接口都会拒绝没有合法令牌的请求
+# 用法: scripts/csrf-check.sh [服务地址]，默认 http://localhost:1323
+
+set -e
+
+BASE_URL="${1:-http://localhost:1323}"
+# 使用不存在的邮箱登录：令牌合法时返回 401 invalid_credentials，被拦截时返回 403 csrf_invalid
+TARGET="$BASE_URL/api/v1/auth/login"
+BODY='{"email":"csrf-check@example.com","password":"not-a-password"}'
+FAILED=0
+
+# 发送请求并输出错误码
+error_code() {
+    curl -s "$@" | sed -n 's/.*"error":{"code":"\([a-z_]*\)".*/\1/p'
+}
+
+# 检查结果是否符合预期
+expect() {
+    local name="$1" expected="$2" actual="$3"
+    if [ "$actual" = "$expected" ]; then
+        echo "✅ $name"
+    else
+        echo "❌ $name: 期望 $expected，实际 ${actual:-无错误码}"
+        FAILED=1
+    fi
+}
+
+echo "🔐 检查 CSRF 防护: $BASE_URL"
+
+# 获取合法令牌和会话Cookie
+JAR="$(mktemp)"
+OTHER_JAR="$(mktemp)"
+trap 'rm -f "$JAR" "$OTHER_JAR"' EXIT
+TOKEN="$(curl -s -c "$JAR" "$BASE_URL/api/v1/auth/csrf" | sed -n 's/.*"token":"\([^"]*\)".*/\1/p')"
+if [ -z "$TOKEN" ]; then
+    echo "❌ 无法获取 CSRF 令牌，请确认服务已启动"
+    exit 1
+fi
+
+# 其他站点的表单提交：浏览器会带上Cookie，但无法设置自定义请求头
+expect "跨站表单提交（只有Cookie）" csrf_invalid \
+    "$(error_code -b "$JAR" -H 'Origin: https://evil.example' -H 'Sec-Fetch-Site: cross-site' \
+        -H 'Content-Type: application/x-www-form-urlencoded' --data 'email=a@b.c&password=x' "$TARGET")"
+
+# 伪造的令牌
+expect "伪造的令牌" csrf_invalid \
+    "$(error_code -b "$JAR" -H "X-CSRF-Token: forged-$TOKEN" -H 'Content-Type: application/json' -d "$BODY" "$TARGET")"
+
+# 攻击者用自己会话的令牌构造请求头，但受害者浏览器中的会话不同
+OTHER_TOKEN="$(curl -s -c "$OTHER_JAR" "$BASE_URL/api/v1/auth/csrf" | sed -n 's/.*"token":"\([^"]*\)".*/\1/p')"
+expect "其他会话的令牌" csrf_invalid \
+    "$(error_code -b "$JAR" -H "X-CSRF-Token: $OTHER_TOKEN" -H 'Content-Type: application/json' -d "$BODY" "$TARGET")"
+
+# 只有请求头没有会话Cookie
+expect "缺少会话Cookie" csrf_invalid \
+    "$(error_code -H "X-CSRF-Token: $TOKEN" -H 'Content-Type: application/json' -d "$BODY" "$TARGET")"
+
+# 空的 Bearer 令牌不能绕过校验
+expect "空的 Bearer 令牌" csrf_invalid \
+    "$(error_code -b "$JAR" -H 'Authorization: Bearer ' -H 'Content-Type: application/json' -d "$BODY" "$TARGET")"
+
+# 未允许的来源无法通过预检携带 X-CSRF-Token
+PREFLIGHT="$(curl -s -o /dev/null -D - -X OPTIONS -H 'Origin: https://evil.example' \
+    -H 'Access-Control-Request-Method: POST' -H 'Access-Control-Request-Headers: x-csrf-token' "$TARGET" |
+    grep -ci '^access-control-allow-origin' || true)"
+expect "未允许来源的预检" 0 "$PREFLIGHT"
+
+# 携带会话Cookie时 Bearer 请求头不能绕过校验
+expect "携带Cookie的 Bearer 请求" csrf_invalid \
+    "$(error_code -b "$JAR" -H 'Authorization: Bearer api-client-token' -H 'Content-Type: application/json' -d "$BODY" "$TARGET")"
+
+# 合法请求：令牌属于当前会话
+expect "携带合法令牌" invalid_credentials \
+    "$(error_code -b "$JAR" -H "X-CSRF-Token: $TOKEN" -H 'Content-Type: application/json' -d "$BODY" "$TARGET")"
+
+# 没有Cookie的 Bearer 客户端不依赖浏览器，跳过校验
+expect "Bearer 客户端" invalid_credentials \
+    "$(error_code -H 'Authorization: Bearer api-client-token' -H 'Content-Type: application/json' -d "$BODY" "$TARGET")"
+
+if [ "$FAILED" -ne 0 ]; then
+    echo "❌ CSRF 防护检查未通过"
+    exit 1
+fi
+
+echo "✅ CSRF 防护检查通过"
@@ -0,0 +1,2 @@
+// Package web 前端构建产物. 使用 -tags embed 构建时将 web/dist 嵌入二进制程序，部署时只需要一个文件
+package web
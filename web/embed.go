@@ -0,0 +1,18 @@
+//go:build embed
+
+package web
+
+import (
+	"embed"
+	"io/fs"
+)
+
+// dist 前端构建产物，构建前需要先执行 pnpm run build 生成 web/dist.
+//
+//go:embed all:dist
+var dist embed.FS
+
+// Dist 返回嵌入的前端构建产物，根目录对应 web/dist.
+func Dist() (fs.FS, error) {
+	return fs.Sub(dist, "dist")
+}
@@ -0,0 +1,10 @@
+//go:build !embed
+
+package web
+
+import "io/fs"
+
+// Dist 未使用 -tags embed 构建时没有嵌入前端构建产物，返回 nil.
+func Dist() (fs.FS, error) {
+	return nil, nil //nolint:nilnil // 没有嵌入时由调用方决定是否需要前端资源
+}
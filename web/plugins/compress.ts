@@ -0,0 +1,85 @@
+/**
+ * Vite 插件：构建时为静态资源生成 Brotli (.br) 和 gzip (.gz) 预压缩文件
+ *
+ * 服务端按请求的 Accept-Encoding 直接返回预压缩文件，不再实时压缩。
+ * HTML 页面需要在返回时替换 CSP nonce，不生成预压缩文件。
+ *
+ * 配置说明：
+ * - extensions: 需要压缩的文件扩展名
+ * - threshold: 小于该字节数的文件不压缩
+ */
+
+import type { Plugin } from "vite";
+import fs from "fs";
+import path from "path";
+import zlib from "zlib";
+
+export interface CompressPluginOptions {
+  extensions?: string[];
+  threshold?: number;
+}
+
+const DEFAULT_EXTENSIONS = [
+  ".js",
+  ".mjs",
+  ".css",
+  ".svg",
+  ".json",
+  ".xml",
+  ".txt",
+  ".ico",
+  ".wasm",
+];
+
+function listFiles(dir: string): string[] {
+  return fs.readdirSync(dir, { withFileTypes: true }).flatMap((entry) => {
+    const fullPath = path.join(dir, entry.name);
+    return entry.isDirectory() ? listFiles(fullPath) : [fullPath];
+  });
+}
+
+export function compressPlugin(options: CompressPluginOptions = {}): Plugin {
+  const { extensions = DEFAULT_EXTENSIONS, threshold = 1024 } = options;
+  let outDir = "dist";
+
+  return {
+    name: "vite-plugin-precompress",
+    apply: "build",
+    configResolved(config) {
+      outDir = path.resolve(config.root, config.build.outDir);
+    },
+    // 在其他插件（如 sitemap）写完文件后执行
+    closeBundle: {
+      order: "post",
+      sequential: true,
+      handler() {
+        let count = 0;
+
+        for (const file of listFiles(outDir)) {
+          if (!extensions.includes(path.extname(file))) continue;
+
+          const data = fs.readFileSync(file);
+          if (data.length < threshold) continue;
+
+          const brotli = zlib.brotliCompressSync(data, {
+            params: {
+              [zlib.constants.BROTLI_PARAM_QUALITY]:
+                zlib.constants.BROTLI_MAX_QUALITY,
+              [zlib.constants.BROTLI_PARAM_SIZE_HINT]: data.length,
+            },
+          });
+          const gzip = zlib.gzipSync(data, {
+            level: zlib.constants.Z_BEST_COMPRESSION,
+          });
+
+          // 压缩后没有变小的文件不需要预压缩版本
+          if (brotli.length < data.length) fs.writeFileSync(`${file}.br`, brotli);
+          if (gzip.length < data.length) fs.writeFileSync(`${file}.gz`, gzip);
+          count++;
+        }
+
+        console.log(`✅ precompressed ${count} files (.br, .gz)`);
+      },
+    },
+  };
+}
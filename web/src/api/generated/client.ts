@@ -0,0 +1,76 @@
+// 由 go run ./cmd/tsgen 生成，请勿手动修改
+import client from "../../lib/client";
+import type { ApiResponse } from "../../lib/client";
+import type { CSRFTokenResponse, GoogleLoginRequest, LegacyHealthResponse, LoginResponse, LoginTokenResponse, UserChangePasswordRequest, UserLoginRequest, UserRegisterRequest, UserResponse, UserUpdateProfileRequest } from "./types";
+
+export const systemApi = {
+  /** @deprecated 服务状态（兼容旧格式） */
+  getHealth: async (): Promise<LegacyHealthResponse> => {
+    const response = await client.get<LegacyHealthResponse>("/api/v1/health");
+    return response.data;
+  },
+};
+
+export const authApi = {
+  /** 获取 CSRF 令牌 */
+  getCsrfToken: async (): Promise<ApiResponse<CSRFTokenResponse>> => {
+    const response = await client.get<ApiResponse<CSRFTokenResponse>>("/api/v1/auth/csrf");
+    return response.data;
+  },
+
+  /** 注册 */
+  register: async (data: UserRegisterRequest): Promise<ApiResponse<UserResponse>> => {
+    const response = await client.post<ApiResponse<UserResponse>>("/api/v1/auth/register", data);
+    return response.data;
+  },
+
+  /** 邮箱密码登录 */
+  login: async (data: UserLoginRequest): Promise<ApiResponse<LoginResponse>> => {
+    const response = await client.post<ApiResponse<LoginResponse>>("/api/v1/auth/login", data);
+    return response.data;
+  },
+
+  /** Google 登录 */
+  googleLogin: async (data: GoogleLoginRequest): Promise<ApiResponse<LoginResponse>> => {
+    const response = await client.post<ApiResponse<LoginResponse>>("/api/v1/auth/google", data);
+    return response.data;
+  },
+
+  /** 注销 */
+  logout: async (): Promise<ApiResponse<null>> => {
+    const response = await client.post<ApiResponse<null>>("/api/v1/auth/logout");
+    return response.data;
+  },
+};
+
+export const userApi = {
+  /** 获取当前用户资料 */
+  getProfile: async (): Promise<ApiResponse<UserResponse>> => {
+    const response = await client.get<ApiResponse<UserResponse>>("/api/v1/user/profile");
+    return response.data;
+  },
+
+  /** 更新个人资料 */
+  updateProfile: async (data: UserUpdateProfileRequest): Promise<ApiResponse<UserResponse>> => {
+    const response = await client.put<ApiResponse<UserResponse>>("/api/v1/user/profile", data);
+    return response.data;
+  },
+
+  /** 修改密码 */
+  changePassword: async (data: UserChangePasswordRequest): Promise<ApiResponse<null>> => {
+    const response = await client.post<ApiResponse<null>>("/api/v1/user/change-password", data);
+    return response.data;
+  },
+
+  /** 获取记住登录的设备 */
+  listLoginTokens: async (): Promise<ApiResponse<LoginTokenResponse[]>> => {
+    const response = await client.get<ApiResponse<LoginTokenResponse[]>>("/api/v1/user/login-tokens");
+    return response.data;
+  },
+
+  /** 退出记住登录的设备 */
+  revokeLoginToken: async (id: string): Promise<ApiResponse<null>> => {
+    const response = await client.delete<ApiResponse<null>>(`/api/v1/user/login-tokens/${encodeURIComponent(id)}`);
+    return response.data;
+  },
+};
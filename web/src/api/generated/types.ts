@@ -0,0 +1,90 @@
+// 由 go run ./cmd/tsgen 生成，请勿手动修改
+
+export interface APIResponse {
+  code: number;
+  data: unknown;
+  message: string;
+  error?: ErrorDetail;
+}
+
+export interface CSRFTokenResponse {
+  token: string;
+}
+
+export interface ErrorDetail {
+  code: string;
+  fields?: FieldDetail[];
+}
+
+export interface FieldDetail {
+  field: string;
+  code: string;
+  message: string;
+}
+
+export interface GoogleLoginRequest {
+  id_token: string;
+  remember_me?: boolean;
+}
+
+export interface LegacyHealthResponse {
+  success: boolean;
+  data: ServiceInfo;
+  message: string;
+}
+
+export interface LoginResponse {
+  user: UserResponse;
+}
+
+export interface LoginTokenResponse {
+  id: string;
+  user_agent: string;
+  ip: string;
+  created_at: string;
+  last_used_at: string;
+  expires_at: string;
+  current: boolean;
+}
+
+export type LoginType = "local" | "google";
+
+export interface ServiceInfo {
+  status: string;
+  service: string;
+  version: string;
+  commit: string;
+}
+
+export interface UserChangePasswordRequest {
+  old_password: string;
+  new_password: string;
+}
+
+export interface UserLoginRequest {
+  email: string;
+  password: string;
+  remember_me?: boolean;
+}
+
+export interface UserRegisterRequest {
+  username: string;
+  email: string;
+  password: string;
+}
+
+export interface UserResponse {
+  id: string;
+  username: string;
+  email: string;
+  avatar_url: string;
+  login_type: LoginType;
+  bio: string;
+  created_at: string;
+}
+
+export interface UserUpdateProfileRequest {
+  username?: string;
+  email?: string;
+  bio?: string;
+}
@@ -3,5 +3,6 @@ export * from "./user";
 export * from "./system";
 
 // 重新导出类型和客户端
-export type { ApiResponse } from "../lib/client";
+export type { ApiResponse, ApiErrorDetail, ApiFieldError } from "../lib/client";
+export { ApiError } from "../lib/client";
 export { default as client } from "../lib/client";
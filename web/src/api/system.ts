@@ -1,12 +1,3 @@
-// 系统相关API接口
-import client from "../lib/client";
-import type { ApiResponse } from "../lib/client";
-
-// 系统API
-export const systemApi = {
-  // 健康检查
-  health: async (): Promise<ApiResponse<null>> => {
-    const response = await client.get<ApiResponse<null>>("/v1/health");
-    return response.data;
-  },
-};
+// 系统相关API接口，由 cmd/tsgen 根据 Go 模型生成
+export { systemApi } from "./generated/client";
+export type { LegacyHealthResponse, ServiceInfo } from "./generated/types";
@@ -1,15 +1,75 @@
 // HTTP客户端配置，基于axios
 import axios from "axios";
-import type { AxiosInstance, AxiosResponse, AxiosError } from "axios";
+import type {
+  AxiosInstance,
+  AxiosResponse,
+  AxiosError,
+  InternalAxiosRequestConfig,
+} from "axios";
+import type { CSRFTokenResponse } from "../api/generated/types";
 
 // API基础URL
 const API_BASE_URL = import.meta.env.VITE_API_BASE_URL || "";
 
+// 字段校验错误
+export interface ApiFieldError {
+  field: string;
+  code: string;
+  message: string;
+}
+
+// 失败响应的错误详情，code 为稳定的字符串错误码（如 email_taken）
+export interface ApiErrorDetail {
+  code: string;
+  fields?: ApiFieldError[];
+}
+
 // 统一的API响应格式
 export interface ApiResponse<T = unknown> {
   code: number;
   data: T;
   message: string;
+  error?: ApiErrorDetail;
+}
+
+// API请求错误，携带HTTP状态码、错误码和字段错误，便于按错误码处理
+export class ApiError extends Error {
+  status?: number;
+  code?: string;
+  fields: ApiFieldError[];
+
+  constructor(message: string, status?: number, detail?: ApiErrorDetail) {
+    super(message);
+    this.name = "ApiError";
+    this.status = status;
+    this.code = detail?.code;
+    this.fields = detail?.fields ?? [];
+  }
+}
+
+// 当前界面语言：优先使用 i18next 保存的语言，其次使用浏览器语言
+function getLanguage(): string {
+  return localStorage.getItem("i18nextLng") || navigator.language || "zh-CN";
+}
+
+// 不修改数据、不需要 CSRF 令牌的请求方法
+const SAFE_METHODS = ["get", "head", "options"];
+
+// CSRF 令牌，首次发送修改数据的请求前获取，之后复用；登录等操作更换会话后从响应头更新
+let csrfToken: Promise<string> | null = null;
+
+// 获取 CSRF 令牌，并发请求共用同一次获取
+function getCsrfToken(): Promise<string> {
+  if (!csrfToken) {
+    csrfToken = client
+      .get<ApiResponse<CSRFTokenResponse>>("/api/v1/auth/csrf")
+      .then((response) => response.data.data.token)
+      .catch((error) => {
+        csrfToken = null;
+        throw error;
+      });
+  }
+  return csrfToken;
 }
 
 // 创建axios实例
@@ -24,8 +84,14 @@ const client: AxiosInstance = axios.create({
 
 // 请求拦截器
 client.interceptors.request.use(
-  (config) => {
+  async (config) => {
     // 不再需要手动设置Authorization头，因为使用了cookie
+    // 告知服务端界面语言，服务端据此返回对应语言的消息
+    config.headers.set("X-Language", getLanguage());
+    // 修改数据的请求需要携带 CSRF 令牌
+    if (!SAFE_METHODS.includes((config.method || "get").toLowerCase())) {
+      config.headers.set("X-CSRF-Token", await getCsrfToken());
+    }
     return config;
   },
   (error) => {
@@ -36,40 +102,55 @@ client.interceptors.request.use(
 // 响应拦截器
 client.interceptors.response.use(
   (response: AxiosResponse<ApiResponse>) => {
+    // 会话更换后服务端通过响应头返回新的 CSRF 令牌
+    const token = response.headers["x-csrf-token"];
+    if (typeof token === "string" && token) {
+      csrfToken = Promise.resolve(token);
+    }
     // 直接返回响应，让调用方处理业务逻辑
     return response;
   },
-  (error: AxiosError) => {
-    // 统一处理错误响应
+  async (error: AxiosError<ApiResponse>) => {
+    // CSRF 令牌失效（如Cookie已过期）时重新获取令牌并重试一次
+    const config = error.config as
+      | (InternalAxiosRequestConfig & { csrfRetried?: boolean })
+      | undefined;
+    if (
+      config &&
+      !config.csrfRetried &&
+      error.response?.status === 403 &&
+      error.response.data?.error?.code === "csrf_invalid"
+    ) {
+      config.csrfRetried = true;
+      csrfToken = null;
+      return client(config);
+    }
+
+    // 统一处理错误响应，优先使用服务端返回的本地化消息
     let message = "网络错误，请稍后重试";
+    let status: number | undefined;
+    let detail: ApiErrorDetail | undefined;
 
     if (error.response) {
       // 服务器返回了错误状态码
-      const { status, data } = error.response;
+      const { data } = error.response;
+      status = error.response.status;
+      detail = data?.error;
 
       switch (status) {
-        case 400:
-          message = (data as { message?: string })?.message || "请求参数错误";
-          break;
         case 401:
-          message = "未授权，请重新登录";
+          message = data?.message || "未授权，请重新登录";
           // 处理登录过期逻辑 - 不再需要清除token，因为使用了cookie
           // 动态导入避免循环依赖
           import("../store/authStore").then(({ useAuthStore }) => {
             useAuthStore.getState().clearAuth();
           });
           break;
-        case 403:
-          message = "权限不足";
-          break;
-        case 404:
-          message = "请求的资源不存在";
-          break;
         case 500:
-          message = "服务器内部错误";
+          message = data?.message || "服务器内部错误";
           break;
         default:
-          message = (data as { message?: string })?.message || `请求失败 (${status})`;
+          message = data?.message || `请求失败 (${status})`;
       }
     } else if (error.request) {
       // 请求已发出但没有收到响应
@@ -77,7 +158,7 @@ client.interceptors.response.use(
     }
 
     console.error("API请求错误:", error);
-    return Promise.reject(new Error(message));
+    return Promise.reject(new ApiError(message, status, detail));
   }
 );
 
@@ -1,9 +1,35 @@
+import { useCallback, useEffect, useState } from "react";
 import { useAuthStore } from "@/store/authStore";
+import { userApi, type LoginToken } from "@/api";
 import { User, Settings, LogOut } from "lucide-react";
 import SEO from "@/components/SEO";
 
 export default function DashboardPage() {
   const { user, logout } = useAuthStore();
+  const [loginTokens, setLoginTokens] = useState<LoginToken[]>([]);
+
+  const loadLoginTokens = useCallback(async () => {
+    try {
+      const response = await userApi.listLoginTokens();
+      if (response.code === 0 && response.data) {
+        setLoginTokens(response.data);
+      }
+    } catch {
+      // 列表只用于展示，加载失败时保持为空
+    }
+  }, []);
+
+  useEffect(() => {
+    loadLoginTokens();
+  }, [loadLoginTokens]);
+
+  const handleRevoke = async (id: string) => {
+    try {
+      await userApi.revokeLoginToken(id);
+    } finally {
+      loadLoginTokens();
+    }
+  };
 
   const handleLogout = () => {
     logout();
@@ -128,6 +154,44 @@ export default function DashboardPage() {
             </div>
           </div>
 
+          {/* Remembered Devices */}
+          {loginTokens.length > 0 && (
+            <div className="bg-white rounded-2xl shadow-sm p-8 mt-8">
+              <h2 className="text-xl font-semibold text-[#1e1e1e] mb-6">
+                Remembered Devices
+              </h2>
+              <div className="space-y-4">
+                {loginTokens.map((token) => (
+                  <div
+                    key={token.id}
+                    className="flex items-center justify-between gap-4 py-3 border-b border-gray-100 last:border-b-0"
+                  >
+                    <div className="min-w-0">
+                      <p className="text-[#1e1e1e] font-medium truncate">
+                        {token.user_agent || "Unknown device"}
+                        {token.current && (
+                          <span className="ml-2 text-xs text-green-600">
+                            (this device)
+                          </span>
+                        )}
+                      </p>
+                      <p className="text-[#666] text-sm">
+                        {token.ip || "-"} · Last used{" "}
+                        {new Date(token.last_used_at).toLocaleString()}
+                      </p>
+                    </div>
+                    <button
+                      onClick={() => handleRevoke(token.id)}
+                      className="shrink-0 px-4 py-2 text-sm text-[#666] hover:text-[#1e1e1e] border border-gray-200 rounded-lg hover:border-gray-300 transition-colors"
+                    >
+                      Sign out
+                    </button>
+                  </div>
+                ))}
+              </div>
+            </div>
+          )}
+
           {/* Quick Actions */}
           <div className="mt-8">
             <h2 className="text-xl font-semibold text-[#1e1e1e] mb-6">
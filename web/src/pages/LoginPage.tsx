@@ -34,10 +34,14 @@ export default function LoginPage() {
   const [formData, setFormData] = useState<LoginRequest>({
     email: "",
     password: "",
+    remember_me: false,
   });
   const [loading, setLoading] = useState(false);
   const [error, setError] = useState("");
   const googleButtonRef = useRef<HTMLDivElement>(null);
+  // Google 登录回调只注册一次，通过 ref 读取最新的"记住我"选项
+  const rememberMeRef = useRef(false);
+  rememberMeRef.current = formData.remember_me ?? false;
 
   const login = useAuthStore((state) => state.login);
   const navigate = useNavigate();
@@ -46,8 +50,8 @@ export default function LoginPage() {
   const from = location.state?.from?.pathname || "/dashboard";
 
   const handleInputChange = (e: React.ChangeEvent<HTMLInputElement>) => {
-    const { name, value } = e.target;
-    setFormData((prev) => ({ ...prev, [name]: value }));
+    const { name, value, type, checked } = e.target;
+    setFormData((prev) => ({ ...prev, [name]: type === "checkbox" ? checked : value }));
     if (error) setError("");
   };
 
@@ -76,7 +80,10 @@ export default function LoginPage() {
     setError("");
 
     try {
-      const response = await userApi.googleLogin({ id_token: credential });
+      const response = await userApi.googleLogin({
+        id_token: credential,
+        remember_me: rememberMeRef.current,
+      });
       if (response.code === 0 && response.data) {
         login(response.data.user);
         navigate(from, { replace: true });
@@ -216,6 +223,24 @@ export default function LoginPage() {
                 />
               </div>
 
+              <label
+                htmlFor="remember_me"
+                className="flex items-center gap-2 text-sm font-bold uppercase cursor-pointer"
+                style={{ color: "var(--color-pixel-black)" }}
+              >
+                <input
+                  id="remember_me"
+                  name="remember_me"
+                  type="checkbox"
+                  checked={formData.remember_me ?? false}
+                  onChange={handleInputChange}
+                  disabled={loading}
+                  className="w-4 h-4"
+                  style={{ accentColor: "var(--color-pixel-coral)" }}
+                />
+                Remember me
+              </label>
+
               {error && (
                 <div
                   className="text-sm font-bold p-3 pixel-border-sm"
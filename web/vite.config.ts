@@ -3,6 +3,7 @@ import react from "@vitejs/plugin-react";
 import tailwindcss from "@tailwindcss/vite";
 import path from "path";
 import { sitemapPlugin } from "./plugins/sitemap";
+import { compressPlugin } from "./plugins/compress";
 
 // 网站域名配置（用于生成 sitemap）
 const SITE_HOSTNAME = "https://mdzz.uk";
@@ -59,7 +60,13 @@ export default defineConfig({
       defaultChangefreq: "weekly",
       defaultPriority: 0.5,
     }),
+    // 生成 .br/.gz 预压缩文件，由服务端按 Accept-Encoding 返回
+    compressPlugin(),
   ],
+  html: {
+    // 为 script/style/link 标签添加 nonce 占位符，服务端返回页面时替换为每个请求的 CSP nonce
+    cspNonce: "__CSP_NONCE__",
+  },
   resolve: {
     alias: {
       "@": path.resolve(__dirname, "./src"),